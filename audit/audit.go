@@ -0,0 +1,109 @@
+// Package audit records the operations run by the telemetry server clients
+// as structured records, and writes them to pluggable sinks.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+)
+
+// Record is the audit record of an operation.
+type Record struct {
+	Time time.Time `json:"time"`
+	// Operation is the full gRPC method name, e.g. /gnmi.gNMI/Get
+	Operation string `json:"operation"`
+	// Event is "start" or "stop" for streaming operations
+	Event     string   `json:"event,omitempty"`
+	User      string   `json:"user,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	Auth      []string `json:"auth,omitempty"`
+	Peer      string   `json:"peer,omitempty"`
+	RequestID string   `json:"request_id"`
+	Target    string   `json:"target,omitempty"`
+	Paths     []string `json:"paths,omitempty"`
+	// Code is the gRPC status code of the result
+	Code     string `json:"code,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Duration int64  `json:"duration_us,omitempty"`
+}
+
+func (r *Record) String() string {
+	data, _ := json.Marshal(r)
+	return string(data)
+}
+
+// Sink is a destination of audit records.
+type Sink interface {
+	Write(r *Record) error
+	Close() error
+}
+
+// Logger writes audit records to all its sinks.
+type Logger struct {
+	mu    sync.Mutex
+	sinks []Sink
+}
+
+// NewLogger returns a Logger writing to sinks.
+func NewLogger(sinks ...Sink) *Logger {
+	return &Logger{sinks: sinks}
+}
+
+// Log writes a record to all the sinks. Failing sinks are logged and
+// skipped, so that a broken sink doesn't hide the records from the others.
+func (l *Logger) Log(r *Record) {
+	if l == nil {
+		return
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, s := range l.sinks {
+		if err := s.Write(r); err != nil {
+			log.V(1).Infof("Failed to write audit record %v: %v", r, err)
+		}
+	}
+}
+
+// Close closes all the sinks.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var err error
+	for _, s := range l.sinks {
+		if e := s.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	l.sinks = nil
+	return err
+}
+
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+// NewSyslogSink returns a sink sending records as JSON to syslog, with the
+// LOCAL4 facility used by the translib audit messages.
+func NewSyslogSink(tag string) (Sink, error) {
+	w, err := syslog.New(syslog.LOG_LOCAL4|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, fmt.Errorf("could not open connection to syslog: %v", err)
+	}
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) Write(r *Record) error {
+	return s.w.Info(r.String())
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readRecords(t *testing.T, fileName string) []Record {
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("invalid record %q in %v: %v", scanner.Text(), fileName, err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "audit.log")
+	ts := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := &Record{Time: ts, Operation: "/gnmi.gNMI/Get", User: "admin", RequestID: "TELEMETRY-1", Code: "OK"}
+	line, _ := json.Marshal(r)
+
+	// Room for two records per file.
+	sink, err := NewFileSink(fileName, int64(2*(len(line)+1)), 2)
+	if err != nil {
+		t.Fatalf("NewFileSink failed: %v", err)
+	}
+	logger := NewLogger(sink)
+	for i := 0; i < 7; i++ {
+		logger.Log(&Record{Operation: r.Operation, User: r.User, RequestID: r.RequestID, Code: r.Code, Time: r.Time})
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	for name, want := range map[string]int{"audit.log": 1, "audit.log.1": 2, "audit.log.2": 2} {
+		records := readRecords(t, filepath.Join(dir, name))
+		if len(records) != want {
+			t.Errorf("got %d records in %v, want %d", len(records), name, want)
+		}
+		for _, got := range records {
+			if got.User != "admin" || got.RequestID != "TELEMETRY-1" || got.Code != "OK" {
+				t.Errorf("got record %v in %v, want %v", got, name, r)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "audit.log.3")); !os.IsNotExist(err) {
+		t.Errorf("got more rotated files than the 2 backups")
+	}
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// fileSink writes records as JSON lines to a file, which is rotated when it
+// reaches maxSize bytes. Rotated files are named file.1 to file.maxBackups,
+// file.1 being the most recent one.
+type fileSink struct {
+	name       string
+	maxSize    int64
+	maxBackups int
+	f          *os.File
+	size       int64
+}
+
+// NewFileSink returns a sink writing records as JSON lines to fileName.
+// The file is not rotated if maxSize is 0.
+func NewFileSink(fileName string, maxSize int64, maxBackups int) (Sink, error) {
+	s := &fileSink{name: fileName, maxSize: maxSize, maxBackups: maxBackups}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	f, err := os.OpenFile(s.name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open audit file: %v", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("could not open audit file: %v", err)
+	}
+	s.f = f
+	s.size = fi.Size()
+	return nil
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+	s.f = nil
+	if s.maxBackups <= 0 {
+		os.Remove(s.name)
+	} else {
+		for i := s.maxBackups - 1; i > 0; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", s.name, i), fmt.Sprintf("%s.%d", s.name, i+1))
+		}
+		if err := os.Rename(s.name, s.name+".1"); err != nil {
+			return err
+		}
+	}
+	return s.open()
+}
+
+func (s *fileSink) Write(r *Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if s.f == nil {
+		// A previous rotation failed, try again.
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(data)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return fmt.Errorf("could not rotate audit file: %v", err)
+		}
+	}
+	n, err := s.f.Write(data)
+	s.size += int64(n)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
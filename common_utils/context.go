@@ -14,6 +14,8 @@ type AuthInfo struct {
 	AuthEnabled bool
 	// Roles
 	Roles []string
+	// Mechanisms that authenticated the user, like cert and password
+	Mechanisms []string
 }
 
 // RequestContext holds metadata about REST request.
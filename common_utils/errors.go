@@ -0,0 +1,27 @@
+package common_utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetError holds the errors of the individual operations of a failed Set
+// request. Errors are kept in the order of the delete, replace and update
+// lists of the request; operations which did not fail have a nil error.
+type SetError struct {
+	Delete  []error
+	Replace []error
+	Update  []error
+}
+
+func (e *SetError) Error() string {
+	var errs []string
+	for _, opErrs := range [][]error{e.Delete, e.Replace, e.Update} {
+		for _, err := range opErrs {
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	return fmt.Sprintf("SET failed: %s", strings.Join(errs, "; "))
+}
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -195,7 +196,7 @@ func (cs *clientSubscription) NewInstance(ctx context.Context) error {
 	if target == "OTHERS" {
 		dc, err = sdc.NewNonDbClient(cs.paths, cs.prefix)
 	} else {
-		dc, err = sdc.NewDbClient(cs.paths, cs.prefix)
+		dc, err = sdc.NewDbClient(cs.paths, cs.prefix, clientCfg.Encoding)
 	}
 	if err != nil {
 		log.V(1).Infof("Connection to DB for %v failed: %v", *cs, err)
@@ -217,7 +218,7 @@ func (cs *clientSubscription) send(stream spb.GNMIDialOut_PublishClient) error {
 			return err
 		}
 		if err != nil {
-			cs.errors++
+			atomic.AddUint64(&cs.errors, 1)
 			log.V(1).Infof("%v", err)
 			return fmt.Errorf("unexpected queue Gext(1): %v", err)
 		}
@@ -225,20 +226,20 @@ func (cs *clientSubscription) send(stream spb.GNMIDialOut_PublishClient) error {
 		var resp *gpb.SubscribeResponse
 		switch v := items[0].(type) {
 		case sdc.Value:
-			if resp, err = sdc.ValToResp(v); err != nil {
-				cs.errors++
+			if resp, err = sdc.ValToResp(v, clientCfg.Encoding); err != nil {
+				atomic.AddUint64(&cs.errors, 1)
 				return err
 			}
 		default:
 			log.V(1).Infof("Unknown data type %v for %s in queue", items[0], cs)
-			cs.errors++
+			atomic.AddUint64(&cs.errors, 1)
 		}
 
-		cs.sendMsg++
+		atomic.AddUint64(&cs.sendMsg, 1)
 		err = stream.Send(resp)
 		if err != nil {
 			log.V(1).Infof("Client %s sending error:%v", cs, err)
-			cs.errors++
+			atomic.AddUint64(&cs.errors, 1)
 			return err
 		}
 		log.V(5).Infof("Client %s done sending, msg count %d, msg %v", cs, cs.sendMsg, resp)
@@ -298,7 +299,7 @@ restart: //Remote server might go down, in that case we restart with next destin
 	cs.client = nil
 	cs.cMu.Unlock()
 
-	cs.conTryCnt++
+	atomic.AddUint64(&cs.conTryCnt, 1)
 	dest := dests[destIdx]
 	destIdx = (destIdx + 1) % destNum
 	c, err = newClient(ctx, dest)
@@ -373,7 +374,7 @@ restart: //Remote server might go down, in that case we restart with next destin
 					goto restart
 				}
 				log.V(6).Infof("cs %s to  %s done", cs.name, dest)
-				cs.sendMsg++
+				atomic.AddUint64(&cs.sendMsg, 1)
 				c.sendMsg++
 
 				time.Sleep(cs.interval)
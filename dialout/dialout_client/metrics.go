@@ -0,0 +1,49 @@
+package telemetry_dialout
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/Azure/sonic-telemetry/metrics"
+)
+
+// subscriptionCollector exposes the counters of the client subscriptions.
+type subscriptionCollector struct{}
+
+func (subscriptionCollector) Collect(w *metrics.Writer) {
+	configMu.Lock()
+	names := make([]string, 0, len(ClientSubscriptionNameMap))
+	for name := range ClientSubscriptionNameMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	subs := make([]*clientSubscription, 0, len(names))
+	for _, name := range names {
+		subs = append(subs, ClientSubscriptionNameMap[name])
+	}
+	configMu.Unlock()
+
+	for _, m := range []struct {
+		name    string
+		help    string
+		typ     string
+		counter func(cs *clientSubscription) *uint64
+	}{
+		{"telemetry_dialout_messages_sent_total", "Messages published to the destinations of a subscription.", "counter",
+			func(cs *clientSubscription) *uint64 { return &cs.sendMsg }},
+		{"telemetry_dialout_errors_total", "Errors publishing a subscription.", "counter",
+			func(cs *clientSubscription) *uint64 { return &cs.errors }},
+		{"telemetry_dialout_connect_attempts_total", "Connection attempts to the destinations of a subscription.", "counter",
+			func(cs *clientSubscription) *uint64 { return &cs.conTryCnt }},
+	} {
+		w.Family(m.name, m.help, m.typ)
+		for _, cs := range subs {
+			w.Sample(m.name, float64(atomic.LoadUint64(m.counter(cs))),
+				"subscription", cs.name, "dst_group", cs.destGroupName, "report_type", cs.reportType.String(), "target", cs.prefix.GetTarget())
+		}
+	}
+}
+
+func init() {
+	metrics.Register(subscriptionCollector{})
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"flag"
 	dc "github.com/Azure/sonic-telemetry/dialout/dialout_client"
+	"github.com/Azure/sonic-telemetry/metrics"
 	log "github.com/golang/glog"
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"golang.org/x/net/context"
@@ -21,6 +22,7 @@ var (
 		Unidirectional: true,
 		TLS:            &tls.Config{},
 	}
+	metricsAddr = flag.String("metrics_addr", "", "Address of the HTTP metrics endpoint, like :8082 or unix:/var/run/dialout_metrics.sock. Optional.")
 )
 
 func init() {
@@ -40,6 +42,15 @@ func main() {
 		<-c
 		cancel()
 	}()
+	if *metricsAddr != "" {
+		lis, err := metrics.Listen(*metricsAddr)
+		if err != nil {
+			log.Exitf("could not open metrics listener: %v", err)
+		}
+		go func() {
+			log.Errorf("Metrics endpoint stopped: %v", metrics.Serve(lis))
+		}()
+	}
 	log.V(1).Infof("Starting telemetry publish client")
 	err := dc.DialOutRun(ctx, &clientCfg)
 	log.V(1).Infof("Exiting telemetry publish client: %v", err)
@@ -0,0 +1,128 @@
+package gnmi
+
+import (
+	"sort"
+
+	"github.com/Azure/sonic-telemetry/common_utils"
+	spb_admin "github.com/Azure/sonic-telemetry/proto/gnoi/admin"
+	log "github.com/golang/glog"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authorizeAdmin checks that the authenticated user of ctx may manage the
+// sessions of the server.
+func (srv *Server) authorizeAdmin(ctx context.Context) (context.Context, error) {
+	ctx, err := authenticate(srv.userAuth(), ctx)
+	if err != nil {
+		return ctx, err
+	}
+	return ctx, srv.checkAdmin(ctx)
+}
+
+// AdminRole is the role having the admin permission when no authorization
+// policy is configured.
+const AdminRole = "admin"
+
+// checkAdmin checks that the user authenticated in ctx has the admin
+// permission, granted by the authorization policy or, without policy, by
+// the AdminRole.
+func (srv *Server) checkAdmin(ctx context.Context) error {
+	policy := srv.authzPolicy()
+	rc, _ := common_utils.GetContext(ctx)
+	if !rc.Auth.AuthEnabled {
+		return nil
+	}
+	var allowed bool
+	if policy != nil {
+		_, allowed = policy.Allowed(rc.Auth.Roles, AuthzAdmin, "", nil)
+	} else {
+		for _, role := range rc.Auth.Roles {
+			if role == AdminRole {
+				allowed = true
+				break
+			}
+		}
+	}
+	if !allowed {
+		log.V(2).Infof("[%s] user %v roles %v denied %v", rc.ID, rc.Auth.User, rc.Auth.Roles, AuthzAdmin)
+		return status.Errorf(codes.PermissionDenied, "user %v is not allowed to manage sessions", rc.Auth.User)
+	}
+	return nil
+}
+
+// findClient returns the Subscribe client of a session.
+func (srv *Server) findClient(id string) (*Client, error) {
+	srv.cMu.Lock()
+	defer srv.cMu.Unlock()
+	for _, c := range srv.clients {
+		if c.id == id {
+			return c, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "session %v not found", id)
+}
+
+func (srv *Server) ListSessions(ctx context.Context, req *spb_admin.ListSessionsRequest) (*spb_admin.ListSessionsResponse, error) {
+	if _, err := srv.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	log.V(1).Info("gNOI: Sonic ListSessions")
+
+	srv.cMu.Lock()
+	clients := make([]*Client, 0, len(srv.clients))
+	for _, c := range srv.clients {
+		clients = append(clients, c)
+	}
+	srv.cMu.Unlock()
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].start.Before(clients[j].start)
+	})
+	resp := &spb_admin.ListSessionsResponse{}
+	for _, c := range clients {
+		resp.Sessions = append(resp.Sessions, c.session())
+	}
+	return resp, nil
+}
+
+func (srv *Server) GetSession(ctx context.Context, req *spb_admin.GetSessionRequest) (*spb_admin.GetSessionResponse, error) {
+	if _, err := srv.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	log.V(1).Infof("gNOI: Sonic GetSession %v", req.GetId())
+
+	c, err := srv.findClient(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &spb_admin.GetSessionResponse{Session: c.session()}, nil
+}
+
+func (srv *Server) TerminateSession(ctx context.Context, req *spb_admin.TerminateSessionRequest) (*spb_admin.TerminateSessionResponse, error) {
+	ctx, err := srv.authorizeAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rc, _ := common_utils.GetContext(ctx)
+	log.V(1).Infof("gNOI: Sonic TerminateSession %v", req.GetId())
+
+	c, err := srv.findClient(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("[%s] Session %v of %v terminated by user %v", rc.ID, c.id, c, rc.Auth.User)
+	c.terminate(status.Errorf(codes.Aborted, "session %s terminated by administrator", c.id))
+	return &spb_admin.TerminateSessionResponse{}, nil
+}
+
+// ListLockouts returns the users and source addresses that failed password
+// authentication recently, and when their lockouts end.
+func (srv *Server) ListLockouts(ctx context.Context, req *spb_admin.ListLockoutsRequest) (*spb_admin.ListLockoutsResponse, error) {
+	if _, err := srv.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	log.V(1).Info("gNOI: Sonic ListLockouts")
+	return &spb_admin.ListLockoutsResponse{Lockouts: authLockouts.list()}, nil
+}
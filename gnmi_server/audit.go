@@ -0,0 +1,155 @@
+package gnmi
+
+import (
+	"time"
+
+	"github.com/Azure/sonic-telemetry/audit"
+	"github.com/Azure/sonic-telemetry/common_utils"
+	spb_jwt "github.com/Azure/sonic-telemetry/proto/gnoi/jwt"
+	transutil "github.com/Azure/sonic-telemetry/transl_utils"
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// auditServerOptions returns the interceptors auditing all the RPCs of the
+// server, or no option if auditing is disabled.
+func auditServerOptions(config *Config) []grpc.ServerOption {
+	if config.Audit == nil {
+		return nil
+	}
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(auditUnaryInterceptor(config.Audit)),
+		grpc.ChainStreamInterceptor(auditStreamInterceptor(config.Audit)),
+	}
+}
+
+// newAuditRecord starts the audit record of a request. The RequestContext
+// is created here so that the request ID and the user authenticated by the
+// handler are known to the audit.
+func newAuditRecord(ctx context.Context, method string) (*audit.Record, *common_utils.RequestContext, context.Context) {
+	rc, ctx := common_utils.GetContext(ctx)
+	r := &audit.Record{
+		Time:      time.Now(),
+		Operation: method,
+		RequestID: rc.ID,
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		r.Peer = p.Addr.String()
+	}
+	return r, rc, ctx
+}
+
+// addAuditRequest records the target and the paths of a request.
+func addAuditRequest(r *audit.Record, req interface{}) {
+	var prefix *gnmipb.Path
+	var paths []*gnmipb.Path
+	switch req := req.(type) {
+	case *gnmipb.GetRequest:
+		prefix = req.GetPrefix()
+		paths = req.GetPath()
+	case *gnmipb.SetRequest:
+		prefix = req.GetPrefix()
+		paths = append(paths, req.GetDelete()...)
+		for _, u := range req.GetReplace() {
+			paths = append(paths, u.GetPath())
+		}
+		for _, u := range req.GetUpdate() {
+			paths = append(paths, u.GetPath())
+		}
+	case *gnmipb.SubscribeRequest:
+		prefix = req.GetSubscribe().GetPrefix()
+		for _, sub := range req.GetSubscribe().GetSubscription() {
+			paths = append(paths, sub.GetPath())
+		}
+	case *spb_jwt.AuthenticateRequest:
+		r.User = req.GetUsername()
+		return
+	default:
+		return
+	}
+
+	r.Target = prefix.GetTarget()
+	for _, path := range paths {
+		var uri string
+		transutil.ConvertToURI(prefix, path, &uri)
+		if uri == "" {
+			uri = "/"
+		}
+		r.Paths = append(r.Paths, uri)
+	}
+}
+
+// finishAuditRecord records the user and the result of a request.
+func finishAuditRecord(r *audit.Record, rc *common_utils.RequestContext, start time.Time, err error) {
+	if rc.Auth.User != "" {
+		r.User = rc.Auth.User
+		r.Roles = rc.Auth.Roles
+		r.Auth = rc.Auth.Mechanisms
+	}
+	r.Code = status.Code(err).String()
+	if err != nil {
+		r.Error = status.Convert(err).Message()
+	}
+	r.Duration = int64(time.Since(start) / time.Microsecond)
+}
+
+func auditUnaryInterceptor(logger *audit.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		r, rc, ctx := newAuditRecord(ctx, info.FullMethod)
+		addAuditRequest(r, req)
+		resp, err := handler(ctx, req)
+		finishAuditRecord(r, rc, r.Time, err)
+		logger.Log(r)
+		return resp, err
+	}
+}
+
+// auditServerStream audits the start of a stream on its first request.
+type auditServerStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	rc      *common_utils.RequestContext
+	logger  *audit.Logger
+	record  *audit.Record
+	started bool
+}
+
+func (s *auditServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *auditServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err != nil || s.started {
+		return err
+	}
+	s.started = true
+	addAuditRequest(s.record, m)
+	start := *s.record
+	start.Time = time.Now()
+	start.Event = "start"
+	if s.rc.Auth.User != "" {
+		start.User = s.rc.Auth.User
+		start.Roles = s.rc.Auth.Roles
+		start.Auth = s.rc.Auth.Mechanisms
+	}
+	s.logger.Log(&start)
+	return nil
+}
+
+func auditStreamInterceptor(logger *audit.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		r, rc, ctx := newAuditRecord(ss.Context(), info.FullMethod)
+		stream := &auditServerStream{ServerStream: ss, ctx: ctx, rc: rc, logger: logger, record: r}
+		err := handler(srv, stream)
+		stop := *r
+		stop.Time = time.Now()
+		stop.Event = "stop"
+		finishAuditRecord(&stop, rc, r.Time, err)
+		logger.Log(&stop)
+		return err
+	}
+}
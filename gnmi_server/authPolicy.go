@@ -0,0 +1,252 @@
+package gnmi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Azure/sonic-telemetry/common_utils"
+	log "github.com/golang/glog"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authMechanisms are the client auth mechanisms, in the order they are
+// tried when no policy rule applies.
+var authMechanisms = []string{"password", "jwt", "cert"}
+
+// authServiceClasses are the RPC classes of the services, the gNMI RPCs
+// being classes of their own.
+var authServiceClasses = map[string]string{
+	"gnoi.system.System":                 "gnoi_system",
+	"gnoi.sonic.SonicService":            "gnoi_sonic",
+	"gnoi.sonic_jwt.SonicJwtService":     "gnoi_jwt",
+	"gnoi.sonic_admin.SonicAdminService": "gnoi_admin",
+}
+
+// AuthPolicy selects the client auth mechanisms accepted by RPC. Rpcs maps
+// a full method name, like /gnmi.gNMI/Set, an RPC class or "default" to
+// the accepted combinations of mechanisms, like "cert+password|jwt": the
+// mechanisms joined by "+" are all required, the combinations separated by
+// "|" are alternatives. The RPC classes are get, set, subscribe,
+// capabilities, gnoi_system, gnoi_sonic, gnoi_jwt and gnoi_admin.
+type AuthPolicy struct {
+	Rpcs map[string]string `json:"rpcs"`
+
+	rules map[string][][]string
+}
+
+// LoadAuthPolicyFile reads an auth policy from a JSON file.
+func LoadAuthPolicyFile(fileName string) (*AuthPolicy, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	policy := &AuthPolicy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("invalid auth policy %v: %v", fileName, err)
+	}
+	if err := policy.init(); err != nil {
+		return nil, fmt.Errorf("invalid auth policy %v: %v", fileName, err)
+	}
+	return policy, nil
+}
+
+func (p *AuthPolicy) init() error {
+	p.rules = make(map[string][][]string)
+	for rpc, expr := range p.Rpcs {
+		var alternatives [][]string
+		for _, alt := range strings.Split(expr, "|") {
+			var mechanisms []string
+			for _, m := range strings.Split(alt, "+") {
+				m = strings.TrimSpace(m)
+				known := false
+				for _, am := range authMechanisms {
+					known = known || am == m
+				}
+				if !known {
+					return fmt.Errorf("unknown mechanism %q for %v, expected one of %v", m, rpc, authMechanisms)
+				}
+				mechanisms = append(mechanisms, m)
+			}
+			alternatives = append(alternatives, mechanisms)
+		}
+		p.rules[rpc] = alternatives
+	}
+	return nil
+}
+
+// alternatives returns the combinations of mechanisms accepted for a full
+// method name, nil when no rule applies.
+func (p *AuthPolicy) alternatives(method string) [][]string {
+	if alts, ok := p.rules[method]; ok {
+		return alts
+	}
+	if alts, ok := p.rules[authRpcClass(method)]; ok {
+		return alts
+	}
+	return p.rules["default"]
+}
+
+// authRpcClass returns the RPC class of a full method name.
+func authRpcClass(method string) string {
+	parts := strings.Split(strings.TrimPrefix(method, "/"), "/")
+	if len(parts) != 2 {
+		return ""
+	}
+	if parts[0] == "gnmi.gNMI" {
+		return strings.ToLower(parts[1])
+	}
+	return authServiceClasses[parts[0]]
+}
+
+var (
+	authPolicyMu sync.RWMutex
+	authPolicy   *AuthPolicy
+)
+
+// SetAuthPolicy selects the client auth mechanisms by RPC with policy.
+// The RPCs without rule accept any of the enabled mechanisms when policy
+// is nil.
+func SetAuthPolicy(policy *AuthPolicy) {
+	authPolicyMu.Lock()
+	defer authPolicyMu.Unlock()
+	authPolicy = policy
+}
+
+// check fails if a rule of the policy requires a mechanism not enabled in
+// modes, which would fail all the RPCs of the rule.
+func (p *AuthPolicy) check(modes AuthTypes) error {
+	rpcs := make([]string, 0, len(p.rules))
+	for rpc := range p.rules {
+		rpcs = append(rpcs, rpc)
+	}
+	sort.Strings(rpcs)
+	for _, rpc := range rpcs {
+		for _, alt := range p.rules[rpc] {
+			for _, m := range alt {
+				if !modes.Enabled(m) {
+					return fmt.Errorf("%v requires the %v mechanism, not enabled by client_auth", rpc, m)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// CheckAuthPolicy fails if the auth policy requires mechanisms not enabled
+// in modes.
+func CheckAuthPolicy(modes AuthTypes) error {
+	authPolicyMu.RLock()
+	policy := authPolicy
+	authPolicyMu.RUnlock()
+	if policy == nil {
+		return nil
+	}
+	return policy.check(modes)
+}
+
+// authAlternatives returns the combinations of mechanisms accepted for the
+// RPC of ctx: the ones of the auth policy, or each enabled mechanism when
+// no rule applies.
+func authAlternatives(ctx context.Context, UserAuth AuthTypes) ([][]string, bool) {
+	authPolicyMu.RLock()
+	policy := authPolicy
+	authPolicyMu.RUnlock()
+	if policy != nil {
+		method, _ := grpc.Method(ctx)
+		if alts := policy.alternatives(method); alts != nil {
+			return alts, true
+		}
+	}
+	var alts [][]string
+	for _, m := range authMechanisms {
+		if UserAuth.Enabled(m) {
+			alts = append(alts, []string{m})
+		}
+	}
+	return alts, false
+}
+
+// authRequirement describes combinations of mechanisms, like
+// "cert+password or jwt".
+func authRequirement(alternatives [][]string) string {
+	var required []string
+	for _, alt := range alternatives {
+		required = append(required, strings.Join(alt, "+"))
+	}
+	return strings.Join(required, " or ")
+}
+
+// authMechanism authenticates the client of ctx with a mechanism.
+func authMechanism(ctx context.Context, mechanism string) (context.Context, error) {
+	var err error
+	switch mechanism {
+	case "password":
+		ctx, err = BasicAuthenAndAuthor(ctx)
+	case "jwt":
+		_, ctx, err = JwtAuthenAndAuthor(ctx)
+	case "cert":
+		ctx, err = ClientCertAuthenAndAuthor(ctx)
+	default:
+		return ctx, fmt.Errorf("unknown mechanism %v", mechanism)
+	}
+	countAuth(mechanism, err == nil)
+	return ctx, err
+}
+
+// authenticateCombinations authenticates the client of ctx with the first
+// combination of mechanisms that all succeed for the same user. Each
+// mechanism is tried once, and only when enabled. The user and roles are
+// the ones of the first mechanism of the combination.
+func authenticateCombinations(UserAuth AuthTypes, ctx context.Context, alternatives [][]string) (context.Context, error) {
+	rc, ctx := common_utils.GetContext(ctx)
+	type result struct {
+		auth common_utils.AuthInfo
+		err  error
+	}
+	results := map[string]*result{}
+	try := func(m string) *result {
+		if r, ok := results[m]; ok {
+			return r
+		}
+		r := &result{err: status.Errorf(codes.Unauthenticated, "%v auth disabled", m)}
+		if UserAuth.Enabled(m) {
+			rc.Auth.User, rc.Auth.Roles = "", nil
+			ctx, r.err = authMechanism(ctx, m)
+			r.auth = rc.Auth
+		}
+		results[m] = r
+		return r
+	}
+
+	for _, alt := range alternatives {
+		first := try(alt[0])
+		ok := first.err == nil
+		for _, m := range alt[1:] {
+			if !ok {
+				break
+			}
+			r := try(m)
+			if r.err == nil && r.auth.User != first.auth.User {
+				log.Infof("[%s] %v user %v does not match %v user %v", rc.ID, m, r.auth.User, alt[0], first.auth.User)
+				ok = false
+			}
+			ok = ok && r.err == nil
+		}
+		if ok {
+			rc.Auth.User = first.auth.User
+			rc.Auth.Roles = first.auth.Roles
+			rc.Auth.Mechanisms = alt
+			return ctx, nil
+		}
+	}
+
+	rc.Auth.User, rc.Auth.Roles, rc.Auth.Mechanisms = "", nil, nil
+	return ctx, status.Error(codes.Unauthenticated, "Unauthenticated")
+}
@@ -0,0 +1,286 @@
+package gnmi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/Azure/sonic-telemetry/common_utils"
+	sdc "github.com/Azure/sonic-telemetry/sonic_data_client"
+	transutil "github.com/Azure/sonic-telemetry/transl_utils"
+	log "github.com/golang/glog"
+	"github.com/jipanyang/gnxi/utils/xpath"
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthzOp is an operation subject to path authorization.
+type AuthzOp string
+
+const (
+	AuthzRead      AuthzOp = "read"
+	AuthzWrite     AuthzOp = "write"
+	AuthzSubscribe AuthzOp = "subscribe"
+	// AuthzAdmin manages the sessions of the server, it is not bound to
+	// targets or paths.
+	AuthzAdmin AuthzOp = "admin"
+)
+
+// AuthzTable is the CONFIG_DB table holding the authorization policy, one
+// rule per key.
+const AuthzTable = "GNMI_AUTHZ"
+
+// AuthzRule grants the users having one of Roles the Operations on the
+// paths under Paths of Targets. "*" stands for any role or target.
+type AuthzRule struct {
+	Name       string    `json:"name"`
+	Roles      []string  `json:"roles"`
+	Targets    []string  `json:"targets"`
+	Paths      []string  `json:"paths"`
+	Operations []AuthzOp `json:"operations"`
+
+	elems [][]*gnmipb.PathElem
+}
+
+// AuthzPolicy maps roles to the targets and paths they may access. A request
+// is allowed when a rule grants the operation on every path of it.
+type AuthzPolicy struct {
+	Rules []AuthzRule `json:"rules"`
+}
+
+// LoadAuthzPolicyFile reads an authorization policy from a JSON file.
+func LoadAuthzPolicyFile(fileName string) (*AuthzPolicy, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	policy := &AuthzPolicy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("invalid authorization policy %v: %v", fileName, err)
+	}
+	if err := policy.init(); err != nil {
+		return nil, fmt.Errorf("invalid authorization policy %v: %v", fileName, err)
+	}
+	return policy, nil
+}
+
+// LoadAuthzPolicyDb reads the authorization policy from the GNMI_AUTHZ table
+// of CONFIG_DB. List fields hold comma separated values, e.g.
+//
+//	GNMI_AUTHZ|operators roles@=netops targets@=COUNTERS_DB,STATE_DB operations@=read,subscribe
+func LoadAuthzPolicyDb() (*AuthzPolicy, error) {
+	entries, err := sdc.GetDbTable("CONFIG_DB", AuthzTable)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	policy := &AuthzPolicy{}
+	for _, name := range names {
+		fv := entries[name]
+		rule := AuthzRule{
+			Name:    name,
+			Roles:   dbListField(fv, "roles"),
+			Targets: dbListField(fv, "targets"),
+			Paths:   dbListField(fv, "paths"),
+		}
+		for _, op := range dbListField(fv, "operations") {
+			rule.Operations = append(rule.Operations, AuthzOp(op))
+		}
+		policy.Rules = append(policy.Rules, rule)
+	}
+	if err := policy.init(); err != nil {
+		return nil, fmt.Errorf("invalid %v table: %v", AuthzTable, err)
+	}
+	return policy, nil
+}
+
+// WatchAuthzPolicyDb reloads the authorization policy of the GNMI_AUTHZ
+// table when it changes, until stop is closed. The policy in use is kept if
+// the table fails to load.
+func (srv *Server) WatchAuthzPolicyDb(stop <-chan struct{}) error {
+	return sdc.WatchDbTable("CONFIG_DB", AuthzTable, stop, func(key string) {
+		policy, err := LoadAuthzPolicyDb()
+		if err != nil {
+			log.Errorf("Failed to reload authorization policy: %v", err)
+			return
+		}
+		srv.settingsMu.Lock()
+		defer srv.settingsMu.Unlock()
+		srv.config.AuthzPolicy = policy
+		log.Infof("Reloaded authorization policy from %v, changed by %v", AuthzTable, key)
+	})
+}
+
+// dbListField returns the values of a list field, stored either as
+// "name@" or "name".
+func dbListField(fv map[string]string, name string) []string {
+	val, ok := fv[name+"@"]
+	if !ok {
+		val = fv[name]
+	}
+	var list []string
+	for _, v := range strings.Split(val, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// init validates the rules and parses their paths.
+func (p *AuthzPolicy) init() error {
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule%d", i)
+		}
+		if len(rule.Roles) == 0 {
+			return fmt.Errorf("rule %v has no roles", rule.Name)
+		}
+		if len(rule.Targets) == 0 {
+			return fmt.Errorf("rule %v has no targets", rule.Name)
+		}
+		for _, op := range rule.Operations {
+			if op != AuthzRead && op != AuthzWrite && op != AuthzSubscribe && op != AuthzAdmin {
+				return fmt.Errorf("rule %v has invalid operation %q", rule.Name, op)
+			}
+		}
+		if len(rule.Paths) == 0 {
+			rule.Paths = []string{"/"}
+		}
+		rule.elems = nil
+		for _, p := range rule.Paths {
+			if strings.Trim(p, "/") == "" {
+				rule.elems = append(rule.elems, nil)
+				continue
+			}
+			path, err := xpath.ToGNMIPath(p)
+			if err != nil {
+				return fmt.Errorf("rule %v has invalid path %q: %v", rule.Name, p, err)
+			}
+			rule.elems = append(rule.elems, path.GetElem())
+		}
+	}
+	return nil
+}
+
+// Allowed tells whether a user having roles may run op on the path of target.
+// It returns the name of the granting rule.
+func (p *AuthzPolicy) Allowed(roles []string, op AuthzOp, target string, path *gnmipb.Path) (string, bool) {
+	for _, rule := range p.Rules {
+		if rule.grants(roles, op, target, path.GetElem()) {
+			return rule.Name, true
+		}
+	}
+	return "", false
+}
+
+func (r *AuthzRule) grants(roles []string, op AuthzOp, target string, elems []*gnmipb.PathElem) bool {
+	if !r.hasOp(op) || !r.hasRole(roles) {
+		return false
+	}
+	if op == AuthzAdmin {
+		return true
+	}
+	if !r.hasTarget(target) {
+		return false
+	}
+	for _, prefix := range r.elems {
+		if pathHasPrefix(elems, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *AuthzRule) hasOp(op AuthzOp) bool {
+	for _, o := range r.Operations {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *AuthzRule) hasRole(roles []string) bool {
+	for _, rr := range r.Roles {
+		if rr == "*" {
+			return true
+		}
+		for _, role := range roles {
+			if rr == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasTarget matches a target, a rule on a DB also covers its namespaces,
+// e.g. CONFIG_DB covers CONFIG_DB/asic0.
+func (r *AuthzRule) hasTarget(target string) bool {
+	for _, t := range r.Targets {
+		if t == "*" || t == target || strings.HasPrefix(target, t+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// pathHasPrefix tells whether the path elems are under the prefix. Prefix
+// elements without keys match any keys; a wildcard in the path only matches
+// a wildcard prefix element.
+func pathHasPrefix(elems, prefix []*gnmipb.PathElem) bool {
+	if len(prefix) > len(elems) {
+		return false
+	}
+	for i, pe := range prefix {
+		if pe.GetName() != "*" && pe.GetName() != elems[i].GetName() {
+			return false
+		}
+		for k, v := range pe.GetKey() {
+			if v != "*" && elems[i].GetKey()[k] != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// authorize checks that the authenticated user of ctx may run op on all the
+// paths. Authorization is skipped when no policy is configured or when user
+// authentication is disabled.
+func (s *Server) authorize(ctx context.Context, op AuthzOp, prefix *gnmipb.Path, paths []*gnmipb.Path) error {
+	policy := s.authzPolicy()
+	if policy == nil {
+		return nil
+	}
+	rc, _ := common_utils.GetContext(ctx)
+	if !rc.Auth.AuthEnabled {
+		return nil
+	}
+
+	target := prefix.GetTarget()
+	for _, path := range paths {
+		elems := append(append([]*gnmipb.PathElem{}, prefix.GetElem()...), path.GetElem()...)
+		fullPath := &gnmipb.Path{Elem: elems}
+		if rule, ok := policy.Allowed(rc.Auth.Roles, op, target, fullPath); ok {
+			log.V(4).Infof("[%s] user %v allowed to %v %v by rule %v", rc.ID, rc.Auth.User, op, fullPath, rule)
+			continue
+		}
+		var uri string
+		transutil.ConvertToURI(prefix, path, &uri)
+		log.V(2).Infof("[%s] user %v roles %v denied %v on target %v path %v", rc.ID, rc.Auth.User, rc.Auth.Roles, op, target, uri)
+		return status.Errorf(codes.PermissionDenied, "user %v is not allowed to %v %v", rc.Auth.User, op, uri)
+	}
+	return nil
+}
@@ -29,11 +29,16 @@ func BasicAuthenAndAuthor(ctx context.Context) (context.Context, error) {
 	} else {
 		return ctx, status.Errorf(codes.Unauthenticated, "No Password Provided")
 	}
+	if err := authLockouts.check(ctx, username); err != nil {
+		return ctx, err
+	}
 	if err := PopulateAuthStruct(username, &rc.Auth, nil); err != nil {
 		glog.Infof("[%s] Failed to retrieve authentication information; %v", rc.ID, err)
+		authLockouts.record(ctx, username, false)
 		return ctx, status.Errorf(codes.Unauthenticated, "")
 	}
 	auth_success, _ := UserPwAuth(username, passwd)
+	authLockouts.record(ctx, username, auth_success)
 	if auth_success == false {
 		return ctx, status.Errorf(codes.PermissionDenied, "Invalid Password")
 	}
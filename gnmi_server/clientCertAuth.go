@@ -1,6 +1,14 @@
 package gnmi
 
 import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/Azure/sonic-telemetry/common_utils"
 	"github.com/golang/glog"
 	"golang.org/x/net/context"
@@ -10,6 +18,132 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// ClientCertIdentitySources are the fields of client certificates the
+// identity of the client can be taken from.
+var ClientCertIdentitySources = []string{"cn", "san_dns", "san_uri", "san_email", "ou"}
+
+// ClientCertMapping is the local user and roles of a client certificate
+// identity.
+type ClientCertMapping struct {
+	// Local user, the identity itself when empty
+	User string `json:"user"`
+	// Roles of the user, the groups of the local user when empty
+	Roles []string `json:"roles"`
+}
+
+var (
+	clientCertMu       sync.RWMutex
+	clientCertSources  = []string{"cn"}
+	clientCertMappings map[string]*ClientCertMapping
+)
+
+// SetClientCertIdentity takes the identity of clients from the fields of
+// their certificate, in order of preference.
+func SetClientCertIdentity(sources []string) error {
+	if len(sources) == 0 {
+		return fmt.Errorf("no client certificate identity source")
+	}
+	for _, source := range sources {
+		known := false
+		for _, s := range ClientCertIdentitySources {
+			known = known || s == source
+		}
+		if !known {
+			return fmt.Errorf("unknown client certificate identity source %v, expected one of %v", source, ClientCertIdentitySources)
+		}
+	}
+	clientCertMu.Lock()
+	defer clientCertMu.Unlock()
+	clientCertSources = sources
+	return nil
+}
+
+// LoadClientCertMap maps client certificate identities to local users and
+// roles with a JSON file, an object of ClientCertMapping by identity.
+// Unmapped identities are local users.
+func LoadClientCertMap(fileName string) error {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	mappings := map[string]*ClientCertMapping{}
+	if err := json.Unmarshal(data, &mappings); err != nil {
+		return fmt.Errorf("invalid client certificate map %v: %v", fileName, err)
+	}
+	for identity, m := range mappings {
+		if m == nil {
+			return fmt.Errorf("invalid client certificate map %v: no mapping of %v", fileName, identity)
+		}
+	}
+	clientCertMu.Lock()
+	defer clientCertMu.Unlock()
+	clientCertMappings = mappings
+	return nil
+}
+
+// WatchClientCertMap reloads the client certificate map when it changes,
+// checking it every interval until stop is closed.
+func WatchClientCertMap(fileName string, interval time.Duration, stop <-chan struct{}) {
+	files := func() []string { return []string{fileName} }
+	watchFiles(files, interval, stop, func() error {
+		if err := LoadClientCertMap(fileName); err != nil {
+			glog.Errorf("Failed to reload client certificate map: %v", err)
+			return err
+		}
+		glog.Infof("Reloaded client certificate map from %v", fileName)
+		return nil
+	})
+}
+
+// clientCertIdentities returns the values of the identity sources of a
+// certificate, in order of preference.
+func clientCertIdentities(cert *x509.Certificate, sources []string) []string {
+	var identities []string
+	for _, source := range sources {
+		switch source {
+		case "cn":
+			identities = append(identities, cert.Subject.CommonName)
+		case "san_dns":
+			identities = append(identities, cert.DNSNames...)
+		case "san_uri":
+			for _, u := range cert.URIs {
+				identities = append(identities, u.String())
+			}
+		case "san_email":
+			identities = append(identities, cert.EmailAddresses...)
+		case "ou":
+			identities = append(identities, cert.Subject.OrganizationalUnit...)
+		}
+	}
+	valid := identities[:0]
+	for _, identity := range identities {
+		if strings.TrimSpace(identity) != "" {
+			valid = append(valid, identity)
+		}
+	}
+	return valid
+}
+
+// clientCertUser returns the local user and roles of a certificate: the
+// ones of its first mapped identity, or its first identity.
+func clientCertUser(cert *x509.Certificate) (string, []string, error) {
+	clientCertMu.RLock()
+	defer clientCertMu.RUnlock()
+	identities := clientCertIdentities(cert, clientCertSources)
+	if len(identities) == 0 {
+		return "", nil, fmt.Errorf("no %v in certificate %v", strings.Join(clientCertSources, ", "), cert.Subject)
+	}
+	for _, identity := range identities {
+		if m, ok := clientCertMappings[identity]; ok {
+			if m.User == "" {
+				return identity, m.Roles, nil
+			}
+			return m.User, m.Roles, nil
+		}
+	}
+	return identities[0], nil, nil
+}
+
 func ClientCertAuthenAndAuthor(ctx context.Context) (context.Context, error) {
 	rc, ctx := common_utils.GetContext(ctx)
 	p, ok := peer.FromContext(ctx)
@@ -23,16 +157,18 @@ func ClientCertAuthenAndAuthor(ctx context.Context) (context.Context, error) {
 	if len(tlsAuth.State.VerifiedChains) == 0 || len(tlsAuth.State.VerifiedChains[0]) == 0 {
 		return ctx, status.Error(codes.Unauthenticated, "could not verify peer certificate")
 	}
+	if err := clientCrls.check(tlsAuth.State.VerifiedChains); err != nil {
+		glog.Infof("[%s] Rejected client certificate: %v", rc.ID, err)
+		return ctx, status.Error(codes.Unauthenticated, "peer certificate revoked")
+	}
 
-	var username string
-
-	username = tlsAuth.State.VerifiedChains[0][0].Subject.CommonName
-
-	if len(username) == 0 {
-		return ctx, status.Error(codes.Unauthenticated, "invalid username in certificate common name.")
+	username, roles, err := clientCertUser(tlsAuth.State.VerifiedChains[0][0])
+	if err != nil {
+		glog.Infof("[%s] Rejected client certificate: %v", rc.ID, err)
+		return ctx, status.Error(codes.Unauthenticated, "invalid username in certificate")
 	}
 
-	if err := PopulateAuthStruct(username, &rc.Auth, nil); err != nil {
+	if err := PopulateAuthStruct(username, &rc.Auth, roles); err != nil {
 		glog.Infof("[%s] Failed to retrieve authentication information; %v", rc.ID, err)
 		return ctx, status.Errorf(codes.Unauthenticated, "")
 	}
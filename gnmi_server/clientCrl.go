@@ -0,0 +1,173 @@
+package gnmi
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// clientCrl is a CRL of a client CA. Its signature is checked against the
+// issuers of the client certificates, and the results remembered.
+type clientCrl struct {
+	file    string
+	list    *pkix.CertificateList
+	issuer  string
+	revoked map[string]bool
+
+	mu       sync.Mutex
+	verified map[string]error
+}
+
+// verify checks that the CRL was signed by issuer.
+func (c *clientCrl) verify(issuer *x509.Certificate) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err, ok := c.verified[string(issuer.Raw)]
+	if !ok {
+		err = issuer.CheckCRLSignature(c.list)
+		c.verified[string(issuer.Raw)] = err
+	}
+	return err
+}
+
+// clientCrlSet holds the CRLs of the files of a directory.
+type clientCrlSet struct {
+	mu   sync.RWMutex
+	crls []*clientCrl
+}
+
+var clientCrls = &clientCrlSet{}
+
+// LoadClientCrlDir rejects the client certificates revoked by the CRLs of
+// the files of a directory, in PEM or DER. The CRLs in use are kept if any
+// file fails to load.
+func LoadClientCrlDir(dir string) error {
+	crls, err := loadCrlDir(dir)
+	if err != nil {
+		return err
+	}
+	clientCrls.mu.Lock()
+	defer clientCrls.mu.Unlock()
+	clientCrls.crls = crls
+	return nil
+}
+
+// WatchClientCrlDir reloads the CRLs when files of the directory are added,
+// removed or changed, checking them every interval until stop is closed.
+func WatchClientCrlDir(dir string, interval time.Duration, stop <-chan struct{}) {
+	files := func() []string {
+		files, _ := crlFiles(dir)
+		return files
+	}
+	watchFiles(files, interval, stop, func() error {
+		if err := LoadClientCrlDir(dir); err != nil {
+			glog.Errorf("Failed to reload client CRLs: %v", err)
+			return err
+		}
+		glog.Infof("Reloaded client CRLs from %v", dir)
+		return nil
+	})
+}
+
+// crlFiles returns the regular files of dir, skipping hidden ones.
+func crlFiles(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, fi := range infos {
+		if fi.Mode().IsRegular() && !strings.HasPrefix(fi.Name(), ".") {
+			files = append(files, filepath.Join(dir, fi.Name()))
+		}
+	}
+	return files, nil
+}
+
+func loadCrlDir(dir string) ([]*clientCrl, error) {
+	files, err := crlFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var crls []*clientCrl
+	now := time.Now()
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		// ParseCRL takes PEM and DER CRLs.
+		list, err := x509.ParseCRL(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CRL %v: %v", file, err)
+		}
+		var issuer pkix.Name
+		issuer.FillFromRDNSequence(&list.TBSCertList.Issuer)
+		if list.HasExpired(now) {
+			glog.Warningf("CRL %v of %v expired at %v, keeping its revocations", file, issuer, list.TBSCertList.NextUpdate)
+		}
+		c := &clientCrl{
+			file:     file,
+			list:     list,
+			issuer:   issuer.String(),
+			revoked:  map[string]bool{},
+			verified: map[string]error{},
+		}
+		for _, rc := range list.TBSCertList.RevokedCertificates {
+			c.revoked[rc.SerialNumber.String()] = true
+		}
+		crls = append(crls, c)
+	}
+	return crls, nil
+}
+
+// verifyClientCrls returns a VerifyPeerCertificate function failing the TLS
+// handshakes of the clients whose certificate was revoked by a CRL, after
+// verify if not nil.
+func verifyClientCrls(verify func([][]byte, [][]*x509.Certificate) error) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, chains [][]*x509.Certificate) error {
+		if verify != nil {
+			if err := verify(rawCerts, chains); err != nil {
+				return err
+			}
+		}
+		if err := clientCrls.check(chains); err != nil {
+			glog.Infof("Rejected client certificate in TLS handshake: %v", err)
+			return err
+		}
+		return nil
+	}
+}
+
+// check fails if a certificate of the verified chains was revoked by a CRL
+// of its issuer.
+func (s *clientCrlSet) check(chains [][]*x509.Certificate) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.crls) == 0 {
+		return nil
+	}
+	for _, chain := range chains {
+		for i := 0; i+1 < len(chain); i++ {
+			cert, issuer := chain[i], chain[i+1]
+			for _, c := range s.crls {
+				if c.issuer != cert.Issuer.String() || !c.revoked[cert.SerialNumber.String()] {
+					continue
+				}
+				if err := c.verify(issuer); err != nil {
+					glog.Warningf("Ignoring CRL %v not signed by %v: %v", c.file, cert.Issuer, err)
+					continue
+				}
+				return fmt.Errorf("certificate %v serial %v revoked by %v", cert.Subject, cert.SerialNumber, c.file)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package gnmi
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	sdc "github.com/Azure/sonic-telemetry/sonic_data_client"
+	transutil "github.com/Azure/sonic-telemetry/transl_utils"
+)
+
+// QueuePolicy is the policy applied to the updates of a client whose send
+// queue is full.
+type QueuePolicy string
+
+const (
+	// QueueConflate replaces the pending update of the same path with the
+	// latest value, or drops the oldest update if there is none.
+	QueueConflate QueuePolicy = "conflate"
+	// QueueDropOldest drops the oldest pending update.
+	QueueDropOldest QueuePolicy = "drop-oldest"
+	// QueueDisconnect closes the client with ResourceExhausted.
+	QueueDisconnect QueuePolicy = "disconnect"
+)
+
+func (p *QueuePolicy) String() string {
+	return string(*p)
+}
+
+func (p *QueuePolicy) Set(policy string) error {
+	switch QueuePolicy(policy) {
+	case QueueConflate, QueueDropOldest, QueueDisconnect:
+		*p = QueuePolicy(policy)
+		return nil
+	}
+	return fmt.Errorf("Expecting one of 'conflate', 'drop-oldest' or 'disconnect'")
+}
+
+var (
+	errQueueClosed   = errors.New("send queue closed")
+	errQueueOverflow = errors.New("send queue full")
+)
+
+// sendQueue holds the updates waiting to be sent to a client. It holds at
+// most limit updates, if limit is not 0. Sync and fatal messages are never
+// dropped, nor counted in the limit. The pending updates are indexed by path
+// only when they may be conflated.
+type sendQueue struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	items     *list.List
+	pending   map[string]*list.Element
+	updates   int
+	limit     int
+	policy    QueuePolicy
+	dropped   int64
+	conflated int64
+	closed    bool
+}
+
+func newSendQueue(limit int, policy QueuePolicy) *sendQueue {
+	q := &sendQueue{
+		items:  list.New(),
+		limit:  limit,
+		policy: policy,
+	}
+	if q.conflates() {
+		q.pending = make(map[string]*list.Element)
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// valueKey identifies the data of an update for conflation: its path and,
+// for tables and wildcard keys, its table keys. The updates of different
+// keys of a path do not replace each other.
+func valueKey(v sdc.Value) string {
+	var uri string
+	transutil.ConvertToURI(v.GetPrefix(), v.GetPath(), &uri)
+	key := v.GetPrefix().GetTarget() + uri
+	if len(v.Keys) > 0 {
+		keys := append([]string(nil), v.Keys...)
+		sort.Strings(keys)
+		key += "|" + strings.Join(keys, ",")
+	}
+	return key
+}
+
+// conflates tells if the pending updates are replaced by the latest value of
+// their path when the queue is full.
+func (q *sendQueue) conflates() bool {
+	return q.limit > 0 && q.policy == QueueConflate
+}
+
+func isUpdate(v sdc.Value) bool {
+	return !v.GetSyncResponse() && v.GetFatal() == ""
+}
+
+// put adds a value to the queue, applying the queue policy if it is full.
+func (q *sendQueue) put(v sdc.Value) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return errQueueClosed
+	}
+	defer q.cond.Signal()
+
+	if !isUpdate(v) {
+		q.items.PushBack(v)
+		return nil
+	}
+
+	var key string
+	if q.conflates() {
+		key = valueKey(v)
+	}
+	if q.limit > 0 && q.updates >= q.limit {
+		switch q.policy {
+		case QueueDisconnect:
+			return errQueueOverflow
+		case QueueConflate:
+			if e, ok := q.pending[key]; ok {
+				e.Value = v
+				q.conflated++
+				return nil
+			}
+		}
+		q.dropOldest()
+	}
+	e := q.items.PushBack(v)
+	if q.conflates() {
+		q.pending[key] = e
+	}
+	q.updates++
+	return nil
+}
+
+// dropOldest removes the oldest update of the queue.
+func (q *sendQueue) dropOldest() {
+	for e := q.items.Front(); e != nil; e = e.Next() {
+		v := e.Value.(sdc.Value)
+		if !isUpdate(v) {
+			continue
+		}
+		q.remove(e)
+		q.dropped++
+		return
+	}
+}
+
+func (q *sendQueue) remove(e *list.Element) {
+	v := q.items.Remove(e).(sdc.Value)
+	if isUpdate(v) {
+		q.updates--
+		if q.conflates() {
+			key := valueKey(v)
+			if q.pending[key] == e {
+				delete(q.pending, key)
+			}
+		}
+	}
+}
+
+// get blocks until a value is available or the queue is closed.
+func (q *sendQueue) get() (sdc.Value, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.items.Len() == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return sdc.Value{}, errQueueClosed
+	}
+	e := q.items.Front()
+	v := e.Value.(sdc.Value)
+	q.remove(e)
+	return v, nil
+}
+
+func (q *sendQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.cond.Broadcast()
+}
+
+// stats returns the queue depth and the counts of dropped and conflated
+// updates.
+func (q *sendQueue) stats() (int64, int64, int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return int64(q.items.Len()), q.dropped, q.conflated
+}
@@ -5,13 +5,17 @@ import (
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/Workiva/go-datastructures/queue"
 	log "github.com/golang/glog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 
+	spb_admin "github.com/Azure/sonic-telemetry/proto/gnoi/admin"
 	sdc "github.com/Azure/sonic-telemetry/sonic_data_client"
+	transutil "github.com/Azure/sonic-telemetry/transl_utils"
 	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
 )
 
@@ -26,10 +30,20 @@ type Client struct {
 	once      chan struct{}
 	mu        sync.RWMutex
 	q         *queue.PriorityQueue
+	sq        *sendQueue
 	subscribe *gnmipb.SubscriptionList
 	// Wait for all sub go routine to finish
 	w     sync.WaitGroup
 	fatal bool
+	// Authorizes the subscription paths, if set
+	authorize func(prefix *gnmipb.Path, paths []*gnmipb.Path) error
+	// Session information reported by the admin service
+	id       string
+	user     string
+	start    time.Time
+	overflow bool
+	// Error returned by Run when the client is terminated by the server
+	termErr error
 }
 
 // NewClient returns a new initialized client.
@@ -38,9 +52,17 @@ func NewClient(addr net.Addr) *Client {
 	return &Client{
 		addr: addr,
 		q:    pq,
+		sq:   newSendQueue(0, QueueConflate),
 	}
 }
 
+// SetQueueLimit bounds the number of updates waiting to be sent to the
+// client, applying policy to the updates in excess. It must be called
+// before Run.
+func (c *Client) SetQueueLimit(limit int, policy QueuePolicy) {
+	c.sq = newSendQueue(limit, policy)
+}
+
 // String returns the target the client is querying.
 func (c *Client) String() string {
 	return c.addr.String()
@@ -81,12 +103,12 @@ func (c *Client) Run(stream gnmipb.GNMI_SubscribeServer) (err error) {
 
 	defer func() {
 		if err != nil {
-			c.errors++
+			atomic.AddInt64(&c.errors, 1)
 		}
 	}()
 
 	query, err := stream.Recv()
-	c.recvMsg++
+	atomic.AddInt64(&c.recvMsg, 1)
 	if err != nil {
 		if err == io.EOF {
 			return grpc.Errorf(codes.Aborted, "stream EOF received before init")
@@ -96,7 +118,9 @@ func (c *Client) Run(stream gnmipb.GNMI_SubscribeServer) (err error) {
 
 	log.V(2).Infof("Client %s recieved initial query %v", c, query)
 
+	c.mu.Lock()
 	c.subscribe = query.GetSubscribe()
+	c.mu.Unlock()
 	extensions := query.GetExtension()
 
 	if c.subscribe == nil {
@@ -119,12 +143,17 @@ func (c *Client) Run(stream gnmipb.GNMI_SubscribeServer) (err error) {
 	if err != nil {
 		return grpc.Errorf(codes.NotFound, "Invalid subscription path: %v %q", err, query)
 	}
+	if c.authorize != nil {
+		if err = c.authorize(prefix, paths); err != nil {
+			return err
+		}
+	}
 	var dc sdc.Client
 
 	if target == "OTHERS" {
 		dc, err = sdc.NewNonDbClient(paths, prefix)
 	} else if _, ok, _, _ := sdc.IsTargetDb(target); ok {
-		dc, err = sdc.NewDbClient(paths, prefix)
+		dc, err = sdc.NewDbClient(paths, prefix, c.subscribe.GetEncoding())
 	} else {
 		/* For any other target or no target create new Transl Client. */
 		dc, err = sdc.NewTranslClient(prefix, paths, ctx, extensions)
@@ -133,6 +162,9 @@ func (c *Client) Run(stream gnmipb.GNMI_SubscribeServer) (err error) {
 	if err != nil {
 		return grpc.Errorf(codes.NotFound, "%v", err)
 	}
+	if err = c.terminatedErr(); err != nil {
+		return err
+	}
 
 	switch mode := c.subscribe.GetMode(); mode {
 	case gnmipb.SubscriptionList_STREAM:
@@ -154,14 +186,113 @@ func (c *Client) Run(stream gnmipb.GNMI_SubscribeServer) (err error) {
 	}
 
 	log.V(1).Infof("Client %s running", c)
+	c.w.Add(1)
+	go c.pump()
 	go c.recv(stream)
 	err = c.send(stream)
 	c.Close()
 	// Wait until all child go routines exited
 	c.w.Wait()
+	if err == nil {
+		// ONCE subscription completed, close the stream with OK status.
+		return nil
+	}
+	if termErr := c.terminatedErr(); termErr != nil {
+		return termErr
+	}
+	if c.isOverflow() {
+		return grpc.Errorf(codes.ResourceExhausted, "send queue of session %s is full", c.id)
+	}
 	return grpc.Errorf(codes.InvalidArgument, "%s", err)
 }
 
+// pump moves the values put by the data client to the send queue, where the
+// queue limit and policy are applied.
+func (c *Client) pump() {
+	defer c.w.Done()
+	for {
+		items, err := c.q.Get(1)
+		if items == nil {
+			log.V(1).Infof("%v", err)
+			return
+		}
+		if err != nil {
+			atomic.AddInt64(&c.errors, 1)
+			log.V(1).Infof("%v", err)
+			c.Close()
+			return
+		}
+
+		for _, item := range items {
+			v, ok := item.(sdc.Value)
+			if !ok {
+				log.V(1).Infof("Unknown data type %v for %s in queue", item, c)
+				atomic.AddInt64(&c.errors, 1)
+				continue
+			}
+			if err = c.sq.put(v); err != nil {
+				if err == errQueueOverflow {
+					log.V(1).Infof("Client %s send queue full, disconnecting", c)
+					c.mu.Lock()
+					c.overflow = true
+					c.mu.Unlock()
+				}
+				c.Close()
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) isOverflow() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.overflow
+}
+
+// terminate closes the client on behalf of the server, Run returning err.
+func (c *Client) terminate(err error) {
+	c.mu.Lock()
+	c.termErr = err
+	c.mu.Unlock()
+	c.Close()
+}
+
+func (c *Client) terminatedErr() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.termErr
+}
+
+// session returns the state of the client as an admin Session.
+func (c *Client) session() *spb_admin.Session {
+	c.mu.RLock()
+	subscribe := c.subscribe
+	c.mu.RUnlock()
+
+	s := &spb_admin.Session{
+		Id:        c.id,
+		Peer:      c.addr.String(),
+		User:      c.user,
+		StartTime: c.start.UnixNano(),
+		SentMsgs:  atomic.LoadInt64(&c.sendMsg),
+		RecvMsgs:  atomic.LoadInt64(&c.recvMsg),
+		Errors:    atomic.LoadInt64(&c.errors),
+	}
+	s.QueueDepth, s.Dropped, s.Conflated = c.sq.stats()
+	if subscribe != nil {
+		prefix := subscribe.GetPrefix()
+		s.Mode = subscribe.GetMode().String()
+		s.Target = prefix.GetTarget()
+		for _, sub := range subscribe.GetSubscription() {
+			var uri string
+			transutil.ConvertToURI(prefix, sub.GetPath(), &uri)
+			s.Paths = append(s.Paths, uri)
+		}
+	}
+	return s
+}
+
 // Closing of client queue is triggered upon end of stream receive or stream error
 // or fatal error of any client go routine .
 // it will cause cancle of client context and exit of the send goroutines.
@@ -175,6 +306,7 @@ func (c *Client) Close() {
 		}
 		c.q.Dispose()
 	}
+	c.sq.close()
 	if c.stop != nil {
 		close(c.stop)
 	}
@@ -192,7 +324,7 @@ func (c *Client) recv(stream gnmipb.GNMI_SubscribeServer) {
 	for {
 		log.V(5).Infof("Client %s blocking on stream.Recv()", c)
 		event, err := stream.Recv()
-		c.recvMsg++
+		atomic.AddInt64(&c.recvMsg, 1)
 
 		switch err {
 		default:
@@ -225,40 +357,34 @@ func (c *Client) recv(stream gnmipb.GNMI_SubscribeServer) {
 	}
 }
 
-// send runs until process Queue returns an error.
+// send runs until process Queue returns an error. For ONCE subscription it
+// returns nil once the sync_response has been sent.
 func (c *Client) send(stream gnmipb.GNMI_SubscribeServer) error {
 	for {
-		items, err := c.q.Get(1)
-
-		if items == nil {
-			log.V(1).Infof("%v", err)
-			return err
-		}
+		v, err := c.sq.get()
 		if err != nil {
-			c.errors++
 			log.V(1).Infof("%v", err)
-			return fmt.Errorf("unexpected queue Gext(1): %v", err)
+			return err
 		}
 
 		var resp *gnmipb.SubscribeResponse
-		switch v := items[0].(type) {
-		case sdc.Value:
-			if resp, err = sdc.ValToResp(v); err != nil {
-				c.errors++
-				return err
-			}
-		default:
-			log.V(1).Infof("Unknown data type %v for %s in queue", items[0], c)
-			c.errors++
+		if resp, err = sdc.ValToResp(v, c.subscribe.GetEncoding()); err != nil {
+			atomic.AddInt64(&c.errors, 1)
+			return err
 		}
 
-		c.sendMsg++
+		atomic.AddInt64(&c.sendMsg, 1)
 		err = stream.Send(resp)
 		if err != nil {
 			log.V(1).Infof("Client %s sending error:%v", c, err)
-			c.errors++
+			atomic.AddInt64(&c.errors, 1)
 			return err
 		}
 		log.V(5).Infof("Client %s done sending, msg count %d, msg %v", c, c.sendMsg, resp)
+
+		if c.subscribe.GetMode() == gnmipb.SubscriptionList_ONCE && resp.GetSyncResponse() {
+			log.V(1).Infof("Client %s ONCE subscription completed", c)
+			return nil
+		}
 	}
 }
@@ -0,0 +1,130 @@
+package gnmi
+
+import (
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+	"github.com/Azure/sonic-telemetry/common_utils"
+	transutil "github.com/Azure/sonic-telemetry/transl_utils"
+	log "github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo details returned by the server.
+const errorDomain = "gnmi.sonic"
+
+// pathError is the error of a single path of a Get or Set request.
+type pathError struct {
+	prefix *gnmipb.Path
+	path   *gnmipb.Path
+	op     gnmipb.UpdateResult_Operation
+	code   codes.Code
+	err    error
+}
+
+// errorCode returns the status code of an error returned by the data clients.
+// Errors carrying no code are reported with the given default code.
+func errorCode(err error, def codes.Code) codes.Code {
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		return s.Code()
+	}
+	switch err.(type) {
+	case tlerr.NotFoundError:
+		return codes.NotFound
+	case tlerr.InvalidArgsError, tlerr.TranslibSyntaxValidationError, tlerr.TranslibCVLFailure:
+		return codes.InvalidArgument
+	case tlerr.AlreadyExistsError:
+		return codes.AlreadyExists
+	case tlerr.NotSupportedError:
+		return codes.Unimplemented
+	case tlerr.AuthorizationError:
+		return codes.PermissionDenied
+	case tlerr.InternalError:
+		return codes.Internal
+	}
+	return def
+}
+
+// newPathError builds the pathError of a failed path.
+func newPathError(prefix, path *gnmipb.Path, op gnmipb.UpdateResult_Operation, err error, def codes.Code) pathError {
+	return pathError{prefix: prefix, path: path, op: op, code: errorCode(err, def), err: err}
+}
+
+// pathErrorStatus returns a status error carrying one google.rpc.ErrorInfo
+// detail per failed path, followed by the extra details if any. The status
+// code is the code of the first failed path.
+func pathErrorStatus(msg string, pathErrs []pathError, extra ...proto.Message) error {
+	st := status.New(pathErrs[0].code, msg)
+	var details []proto.Message
+	for _, pe := range pathErrs {
+		var uri string
+		transutil.ConvertToURI(pe.prefix, pe.path, &uri)
+		if uri == "" {
+			uri = "/"
+		}
+		info := &errdetails.ErrorInfo{
+			Reason: code.Code_name[int32(pe.code)],
+			Domain: errorDomain,
+			Metadata: map[string]string{
+				"path":    uri,
+				"message": pe.err.Error(),
+			},
+		}
+		if pe.op != gnmipb.UpdateResult_INVALID {
+			info.Metadata["operation"] = pe.op.String()
+		}
+		details = append(details, info)
+	}
+	details = append(details, extra...)
+
+	stWithDetails, err := st.WithDetails(details...)
+	if err != nil {
+		log.V(2).Infof("Failed to add error details: %v", err)
+		return st.Err()
+	}
+	return stWithDetails.Err()
+}
+
+// setErrorStatus converts the error of a Set request to a status error with
+// the error of each failed operation as details. Errors not attributable to
+// an operation are reported with the default code.
+func setErrorStatus(req *gnmipb.SetRequest, err error, def codes.Code) error {
+	var pathErrs []pathError
+	prefix := req.GetPrefix()
+	if setErr, ok := err.(*common_utils.SetError); ok {
+		for i, e := range setErr.Delete {
+			if e != nil && i < len(req.GetDelete()) {
+				pathErrs = append(pathErrs, newPathError(prefix, req.GetDelete()[i], gnmipb.UpdateResult_DELETE, e, def))
+			}
+		}
+		for i, e := range setErr.Replace {
+			if e != nil && i < len(req.GetReplace()) {
+				pathErrs = append(pathErrs, newPathError(prefix, req.GetReplace()[i].GetPath(), gnmipb.UpdateResult_REPLACE, e, def))
+			}
+		}
+		for i, e := range setErr.Update {
+			if e != nil && i < len(req.GetUpdate()) {
+				pathErrs = append(pathErrs, newPathError(prefix, req.GetUpdate()[i].GetPath(), gnmipb.UpdateResult_UPDATE, e, def))
+			}
+		}
+	} else if len(req.GetDelete())+len(req.GetReplace())+len(req.GetUpdate()) == 1 {
+		// A single operation owns the error.
+		for _, path := range req.GetDelete() {
+			pathErrs = append(pathErrs, newPathError(prefix, path, gnmipb.UpdateResult_DELETE, err, def))
+		}
+		for _, u := range req.GetReplace() {
+			pathErrs = append(pathErrs, newPathError(prefix, u.GetPath(), gnmipb.UpdateResult_REPLACE, err, def))
+		}
+		for _, u := range req.GetUpdate() {
+			pathErrs = append(pathErrs, newPathError(prefix, u.GetPath(), gnmipb.UpdateResult_UPDATE, err, def))
+		}
+	}
+
+	if len(pathErrs) == 0 {
+		return status.Error(errorCode(err, def), err.Error())
+	}
+	return pathErrorStatus(err.Error(), pathErrs)
+}
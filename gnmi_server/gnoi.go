@@ -12,11 +12,11 @@ import (
 	"google.golang.org/grpc/codes"
 	"os/user"
 	"encoding/json"
-	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/Azure/sonic-telemetry/common_utils"
 )
 
 func (srv *Server) Reboot(ctx context.Context, req *gnoi_system_pb.RebootRequest) (*gnoi_system_pb.RebootResponse, error) {
-	_, err := authenticate(srv.config.UserAuth, ctx)
+	_, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +24,7 @@ func (srv *Server) Reboot(ctx context.Context, req *gnoi_system_pb.RebootRequest
 	return nil, status.Errorf(codes.Unimplemented, "")
 }
 func (srv *Server) RebootStatus(ctx context.Context, req *gnoi_system_pb.RebootStatusRequest) (*gnoi_system_pb.RebootStatusResponse, error) {
-	_, err := authenticate(srv.config.UserAuth, ctx)
+	_, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +32,7 @@ func (srv *Server) RebootStatus(ctx context.Context, req *gnoi_system_pb.RebootS
 	return nil, status.Errorf(codes.Unimplemented, "")
 }
 func (srv *Server) CancelReboot(ctx context.Context, req *gnoi_system_pb.CancelRebootRequest) (*gnoi_system_pb.CancelRebootResponse, error) {
-	_, err := authenticate(srv.config.UserAuth, ctx)
+	_, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +41,7 @@ func (srv *Server) CancelReboot(ctx context.Context, req *gnoi_system_pb.CancelR
 }
 func (srv *Server) Ping(req *gnoi_system_pb.PingRequest, rs gnoi_system_pb.System_PingServer) error {
 	ctx := rs.Context()
-	_, err := authenticate(srv.config.UserAuth, ctx)
+	_, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return err
 	}
@@ -50,7 +50,7 @@ func (srv *Server) Ping(req *gnoi_system_pb.PingRequest, rs gnoi_system_pb.Syste
 }
 func (srv *Server) Traceroute(req *gnoi_system_pb.TracerouteRequest, rs gnoi_system_pb.System_TracerouteServer) error {
 	ctx := rs.Context()
-	_, err := authenticate(srv.config.UserAuth, ctx)
+	_, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return err
 	}
@@ -59,7 +59,7 @@ func (srv *Server) Traceroute(req *gnoi_system_pb.TracerouteRequest, rs gnoi_sys
 }
 func (srv *Server) SetPackage(rs gnoi_system_pb.System_SetPackageServer) error {
 	ctx := rs.Context()
-	_, err := authenticate(srv.config.UserAuth, ctx)
+	_, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return err
 	}
@@ -67,7 +67,7 @@ func (srv *Server) SetPackage(rs gnoi_system_pb.System_SetPackageServer) error {
 	return status.Errorf(codes.Unimplemented, "")
 }
 func (srv *Server) SwitchControlProcessor(ctx context.Context, req *gnoi_system_pb.SwitchControlProcessorRequest) (*gnoi_system_pb.SwitchControlProcessorResponse, error) {
-	_, err := authenticate(srv.config.UserAuth, ctx)
+	_, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +75,7 @@ func (srv *Server) SwitchControlProcessor(ctx context.Context, req *gnoi_system_
 	return nil, status.Errorf(codes.Unimplemented, "")
 }
 func (srv *Server) Time(ctx context.Context, req *gnoi_system_pb.TimeRequest) (*gnoi_system_pb.TimeResponse, error) {
-	_, err := authenticate(srv.config.UserAuth, ctx)
+	_, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -94,10 +94,15 @@ func (srv *Server) Authenticate(ctx context.Context, req *spb_jwt.AuthenticateRe
 	log.V(1).Info("gNOI: Sonic Authenticate")
 
 
-	if !srv.config.UserAuth.Enabled("jwt") {
+	if !srv.userAuth().Enabled("jwt") {
 		return nil, status.Errorf(codes.Unimplemented, "")
 	}
+	if err := authLockouts.check(ctx, req.Username); err != nil {
+		return nil, err
+	}
 	auth_success, _ := UserPwAuth(req.Username, req.Password)
+	authLockouts.record(ctx, req.Username, auth_success)
+	countAuth("password", auth_success)
 	if  auth_success {
 		usr, err := user.Lookup(req.Username)
 		if err == nil {
@@ -112,13 +117,13 @@ func (srv *Server) Authenticate(ctx context.Context, req *spb_jwt.AuthenticateRe
 
 }
 func (srv *Server) Refresh(ctx context.Context, req *spb_jwt.RefreshRequest) (*spb_jwt.RefreshResponse, error) {
-	ctx, err := authenticate(srv.config.UserAuth, ctx)
+	ctx, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
 	log.V(1).Info("gNOI: Sonic Refresh")
 
-	if !srv.config.UserAuth.Enabled("jwt") {
+	if !srv.userAuth().Enabled("jwt") {
 		return nil, status.Errorf(codes.Unimplemented, "")
 	}
 
@@ -127,10 +132,13 @@ func (srv *Server) Refresh(ctx context.Context, req *spb_jwt.RefreshRequest) (*s
 		return nil, err
 	}
 
-	claims := &Claims{}
-	jwt.ParseWithClaims(token.AccessToken, claims, func(token *jwt.Token) (interface{}, error) {
-		return hmacSampleSecret, nil
-	})
+	claims, err := parseJwtToken(token.AccessToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+	if claims.Issuer != "" {
+		return nil, status.Errorf(codes.InvalidArgument, "JWT Token of %v must be refreshed by its issuer", claims.Issuer)
+	}
 	if time.Unix(claims.ExpiresAt, 0).Sub(time.Now()) > JwtRefreshInt {
 		return nil, status.Errorf(codes.InvalidArgument, "Invalid JWT Token")
 	}
@@ -139,8 +147,71 @@ func (srv *Server) Refresh(ctx context.Context, req *spb_jwt.RefreshRequest) (*s
 
 }
 
+// Revoke rejects the token of the request until it expires, or all the
+// tokens issued to a user so far. Revoking the tokens of other users
+// requires the admin permission.
+func (srv *Server) Revoke(ctx context.Context, req *spb_jwt.RevokeRequest) (*spb_jwt.RevokeResponse, error) {
+	ctx, err := authenticate(srv.userAuth(), ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.V(1).Info("gNOI: Sonic Revoke")
+
+	if !srv.userAuth().Enabled("jwt") {
+		return nil, status.Errorf(codes.Unimplemented, "")
+	}
+	rc, _ := common_utils.GetContext(ctx)
+
+	if req.Username == "" {
+		token, _, err := JwtAuthenAndAuthor(ctx)
+		if err != nil {
+			return nil, err
+		}
+		claims, err := parseJwtToken(token.AccessToken)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, err.Error())
+		}
+		if claims.Id == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "JWT Token without ID, revoke the tokens of user %v instead", claims.Username)
+		}
+		if err := RevokeJwtToken(claims); err != nil {
+			log.Errorf("[%s] Failed to persist revoked token %v: %v", rc.ID, claims.Id, err)
+			return nil, status.Errorf(codes.Internal, "token revoked until restart only: %v", err)
+		}
+		log.Infof("[%s] Token %v of user %v revoked", rc.ID, claims.Id, claims.Username)
+		return &spb_jwt.RevokeResponse{}, nil
+	}
+
+	if req.Username != rc.Auth.User {
+		if err := srv.checkAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := RevokeJwtUser(req.Username); err != nil {
+		log.Errorf("[%s] Failed to persist revoked tokens of user %v: %v", rc.ID, req.Username, err)
+		return nil, status.Errorf(codes.Internal, "tokens revoked until restart only: %v", err)
+	}
+	log.Infof("[%s] Tokens of user %v revoked by user %v", rc.ID, req.Username, rc.Auth.User)
+	return &spb_jwt.RevokeResponse{}, nil
+}
+
+// PublicKeys returns the public keys of the tokens signed with RS256 and
+// ES256 keys. They are not secret, so no authentication is required.
+func (srv *Server) PublicKeys(ctx context.Context, req *spb_jwt.PublicKeysRequest) (*spb_jwt.PublicKeysResponse, error) {
+	log.V(1).Info("gNOI: Sonic PublicKeys")
+
+	if !srv.userAuth().Enabled("jwt") {
+		return nil, status.Errorf(codes.Unimplemented, "")
+	}
+	jwks, err := PublicJwks()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &spb_jwt.PublicKeysResponse{Jwks: string(jwks)}, nil
+}
+
 func (srv *Server) ClearNeighbors(ctx context.Context, req *spb.ClearNeighborsRequest) (*spb.ClearNeighborsResponse, error) {
-    ctx, err := authenticate(srv.config.UserAuth, ctx)
+    ctx, err := authenticate(srv.userAuth(), ctx)
     if err != nil {
         return nil, err
     }
@@ -172,7 +243,7 @@ func (srv *Server) ClearNeighbors(ctx context.Context, req *spb.ClearNeighborsRe
 }
 
 func (srv *Server) CopyConfig(ctx context.Context, req *spb.CopyConfigRequest) (*spb.CopyConfigResponse, error) {
-	ctx, err := authenticate(srv.config.UserAuth, ctx)
+	ctx, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -203,7 +274,7 @@ func (srv *Server) CopyConfig(ctx context.Context, req *spb.CopyConfigRequest) (
 }
 
 func (srv *Server) ShowTechsupport(ctx context.Context, req *spb.TechsupportRequest) (*spb.TechsupportResponse, error) {
-	ctx, err := authenticate(srv.config.UserAuth, ctx)
+	ctx, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -235,7 +306,7 @@ func (srv *Server) ShowTechsupport(ctx context.Context, req *spb.TechsupportRequ
 }
 
 func (srv *Server) ImageInstall(ctx context.Context, req *spb.ImageInstallRequest) (*spb.ImageInstallResponse, error) {
-	ctx, err := authenticate(srv.config.UserAuth, ctx)
+	ctx, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -267,7 +338,7 @@ func (srv *Server) ImageInstall(ctx context.Context, req *spb.ImageInstallReques
 }
 
 func (srv *Server) ImageRemove(ctx context.Context, req *spb.ImageRemoveRequest) (*spb.ImageRemoveResponse, error) {
-	ctx, err := authenticate(srv.config.UserAuth, ctx)
+	ctx, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -299,7 +370,7 @@ func (srv *Server) ImageRemove(ctx context.Context, req *spb.ImageRemoveRequest)
 }
 
 func (srv *Server) ImageDefault(ctx context.Context, req *spb.ImageDefaultRequest) (*spb.ImageDefaultResponse, error) {
-	ctx, err := authenticate(srv.config.UserAuth, ctx)
+	ctx, err := authenticate(srv.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
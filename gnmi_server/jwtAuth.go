@@ -2,7 +2,6 @@ package gnmi
 
 import (
 	"github.com/Azure/sonic-telemetry/common_utils"
-	"crypto/rand"
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/golang/glog"
 	"golang.org/x/net/context"
@@ -14,9 +13,8 @@ import (
 )
 
 var (
-	JwtRefreshInt    time.Duration
-	JwtValidInt      time.Duration
-	hmacSampleSecret = make([]byte, 16)
+	JwtRefreshInt time.Duration
+	JwtValidInt   time.Duration
 )
 
 type Credentials struct {
@@ -27,30 +25,37 @@ type Credentials struct {
 type Claims struct {
 	Username string   `json:"username"`
 	Roles    []string `json:"roles"`
+	// IssuedAtNs is the issue time in nanoseconds of the tokens of the
+	// server, for the revocations of users to apply to the tokens issued
+	// earlier in the same second only.
+	IssuedAtNs int64 `json:"iat_ns,omitempty"`
 	jwt.StandardClaims
 }
 
 func generateJWT(username string, roles []string, expire_dt time.Time) string {
 	// Create a new token object, specifying signing method and the claims
 	// you would like it to contain.
+	now := time.Now()
 	claims := &Claims{
-		Username: username,
-		Roles:    roles,
+		Username:   username,
+		Roles:      roles,
+		IssuedAtNs: now.UnixNano(),
 		StandardClaims: jwt.StandardClaims{
 			// In JWT, the expiry time is expressed as unix milliseconds
 			ExpiresAt: expire_dt.Unix(),
+			Id:        jwtTokenId(),
+			IssuedAt:  now.Unix(),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	key := signingJwtKey()
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.Kid
 
-	// Sign and get the complete encoded token as a string using the secret
-	tokenString, _ := token.SignedString(hmacSampleSecret)
+	// Sign and get the complete encoded token as a string using the key
+	tokenString, _ := token.SignedString(key.signKey)
 
 	return tokenString
 }
-func GenerateJwtSecretKey() {
-	rand.Read(hmacSampleSecret)
-}
 
 func tokenResp(username string, roles []string) *spb.JwtToken {
 	exp_tm := time.Now().Add(JwtValidInt)
@@ -72,15 +77,12 @@ func JwtAuthenAndAuthor(ctx context.Context) (*spb.JwtToken, context.Context, er
 		return nil, ctx, status.Errorf(codes.Unauthenticated, "No JWT Token Provided")
 	}
 
-	claims := &Claims{}
-	tkn, err := jwt.ParseWithClaims(token.AccessToken, claims, func(token *jwt.Token) (interface{}, error) {
-		return hmacSampleSecret, nil
-	})
+	claims, err := parseJwtToken(token.AccessToken)
 	if err != nil {
 		return &token, ctx, status.Errorf(codes.Unauthenticated, err.Error())
 	}
-	if !tkn.Valid {
-		return &token, ctx, status.Errorf(codes.Unauthenticated, "Invalid JWT Token")
+	if jwtRevoked.revoked(claims) {
+		return &token, ctx, status.Errorf(codes.Unauthenticated, "Revoked JWT Token")
 	}
 	if err := PopulateAuthStruct(claims.Username, &rc.Auth, claims.Roles); err != nil {
 		glog.Infof("[%s] Failed to retrieve authentication information; %v", rc.ID, err)
@@ -0,0 +1,374 @@
+package gnmi
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/golang/glog"
+)
+
+// JwtJwksFile is the file the public keys of the asymmetric signing keys
+// are written to as a JWK set when they are loaded. Optional.
+var JwtJwksFile string
+
+// jwtKey is a key signing and verifying JWT tokens, identified by the kid
+// header of the tokens. HS256 keys are base64 secrets, RS256 and ES256 keys
+// PEM private key files.
+type jwtKey struct {
+	Kid            string `json:"kid"`
+	Alg            string `json:"alg"`
+	Secret         string `json:"secret"`
+	PrivateKeyFile string `json:"private_key_file"`
+
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// jwtKeySet holds the keys verifying the tokens. The last key of the set
+// signs the new tokens, the other ones verify the tokens signed before it
+// was introduced, until they expire.
+type jwtKeySet struct {
+	Keys    []*jwtKey `json:"keys"`
+	byKid   map[string]*jwtKey
+	signing *jwtKey
+}
+
+var (
+	jwtKeysMu sync.RWMutex
+	jwtKeys   *jwtKeySet
+)
+
+func init() {
+	GenerateJwtSecretKey()
+}
+
+// checkPrivateFile fails if a file holding keys is accessible by group or
+// others.
+func checkPrivateFile(fileName string) error {
+	fi, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+	if fi.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("%v must not be accessible by group or others, mode is %v", fileName, fi.Mode().Perm())
+	}
+	return nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM private key")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, fmt.Errorf("unsupported %T private key", key)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("invalid %v", block.Type)
+}
+
+// load sets the signing and verifying keys of the key.
+func (k *jwtKey) load() error {
+	if k.Alg == "" {
+		k.Alg = jwt.SigningMethodHS256.Alg()
+	}
+	switch k.Alg {
+	case jwt.SigningMethodHS256.Alg():
+		k.method = jwt.SigningMethodHS256
+		if k.signKey == nil {
+			secret, err := base64.StdEncoding.DecodeString(k.Secret)
+			if err != nil {
+				return fmt.Errorf("invalid secret: %v", err)
+			}
+			k.signKey = secret
+		}
+		if len(k.signKey.([]byte)) < 16 {
+			return fmt.Errorf("secret is shorter than 16 bytes")
+		}
+		k.verifyKey = k.signKey
+		return nil
+	case jwt.SigningMethodRS256.Alg():
+		k.method = jwt.SigningMethodRS256
+	case jwt.SigningMethodES256.Alg():
+		k.method = jwt.SigningMethodES256
+	default:
+		return fmt.Errorf("unsupported algorithm %v", k.Alg)
+	}
+
+	if k.signKey == nil {
+		if k.PrivateKeyFile == "" {
+			return fmt.Errorf("no private_key_file")
+		}
+		if err := checkPrivateFile(k.PrivateKeyFile); err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(k.PrivateKeyFile)
+		if err != nil {
+			return err
+		}
+		signer, err := parsePrivateKey(data)
+		if err != nil {
+			return fmt.Errorf("invalid private key %v: %v", k.PrivateKeyFile, err)
+		}
+		k.signKey = signer
+	}
+	switch key := k.signKey.(type) {
+	case *rsa.PrivateKey:
+		if k.method != jwt.SigningMethodRS256 {
+			return fmt.Errorf("RSA private key for %v", k.Alg)
+		}
+		if key.N.BitLen() < 2048 {
+			return fmt.Errorf("RSA private key is shorter than 2048 bits")
+		}
+		k.verifyKey = &key.PublicKey
+	case *ecdsa.PrivateKey:
+		if k.method != jwt.SigningMethodES256 || key.Curve != elliptic.P256() {
+			return fmt.Errorf("ECDSA %v private key for %v", key.Curve.Params().Name, k.Alg)
+		}
+		k.verifyKey = &key.PublicKey
+	default:
+		return fmt.Errorf("%T private key for %v", key, k.Alg)
+	}
+	return nil
+}
+
+func (ks *jwtKeySet) init() error {
+	if len(ks.Keys) == 0 {
+		return fmt.Errorf("no key")
+	}
+	ks.byKid = make(map[string]*jwtKey)
+	for _, k := range ks.Keys {
+		if k.Kid == "" {
+			return fmt.Errorf("key without kid")
+		}
+		if _, ok := ks.byKid[k.Kid]; ok {
+			return fmt.Errorf("duplicate key %v", k.Kid)
+		}
+		if err := k.load(); err != nil {
+			return fmt.Errorf("key %v: %v", k.Kid, err)
+		}
+		ks.byKid[k.Kid] = k
+	}
+	ks.signing = ks.Keys[len(ks.Keys)-1]
+	return nil
+}
+
+// jwk is the JSON Web Key of the public key of an asymmetric key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// jwkInt encodes an integer of size bytes, padded with leading zeros.
+func jwkInt(n *big.Int, size int) string {
+	b := n.Bytes()
+	if len(b) < size {
+		b = append(make([]byte, size-len(b)), b...)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// jwks returns the JWK set of the public keys of the set. HS256 keys are
+// secret and left out.
+func (ks *jwtKeySet) jwks() ([]byte, error) {
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{Keys: []jwk{}}
+	for _, k := range ks.Keys {
+		switch key := k.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, jwk{Kty: "RSA", Kid: k.Kid, Alg: k.Alg, Use: "sig",
+				N: jwkInt(key.N, 0), E: jwkInt(big.NewInt(int64(key.E)), 0)})
+		case *ecdsa.PublicKey:
+			size := (key.Curve.Params().BitSize + 7) / 8
+			set.Keys = append(set.Keys, jwk{Kty: "EC", Kid: k.Kid, Alg: k.Alg, Use: "sig",
+				Crv: key.Curve.Params().Name, X: jwkInt(key.X, size), Y: jwkInt(key.Y, size)})
+		}
+	}
+	return json.MarshalIndent(set, "", "  ")
+}
+
+// PublicJwks returns the JWK set of the public keys verifying the tokens
+// signed by the RS256 and ES256 keys.
+func PublicJwks() ([]byte, error) {
+	jwtKeysMu.RLock()
+	defer jwtKeysMu.RUnlock()
+	return jwtKeys.jwks()
+}
+
+func setJwtKeys(ks *jwtKeySet) error {
+	if JwtJwksFile != "" {
+		data, err := ks.jwks()
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(JwtJwksFile, data, 0644); err != nil {
+			return fmt.Errorf("could not write JWKS file: %v", err)
+		}
+	}
+	jwtKeysMu.Lock()
+	defer jwtKeysMu.Unlock()
+	jwtKeys = ks
+	return nil
+}
+
+func signingJwtKey() *jwtKey {
+	jwtKeysMu.RLock()
+	defer jwtKeysMu.RUnlock()
+	return jwtKeys.signing
+}
+
+// jwtKeyFunc returns the key verifying a token, by its kid header. Tokens
+// without kid are verified by the signing key. The algorithm of the token
+// must be the one of its key.
+func jwtKeyFunc(token *jwt.Token) (interface{}, error) {
+	jwtKeysMu.RLock()
+	defer jwtKeysMu.RUnlock()
+	k := jwtKeys.signing
+	if kid, ok := token.Header["kid"].(string); ok {
+		if k, ok = jwtKeys.byKid[kid]; !ok {
+			return nil, fmt.Errorf("unknown key %v", kid)
+		}
+	}
+	if token.Method != k.method {
+		return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+	}
+	return k.verifyKey, nil
+}
+
+// GenerateJwtKey signs and verifies tokens with a random key of an
+// algorithm, HS256, RS256 or ES256, valid until the server restarts.
+func GenerateJwtKey(alg string) error {
+	var signKey interface{}
+	var err error
+	switch alg {
+	case jwt.SigningMethodHS256.Alg():
+		secret := make([]byte, 16)
+		_, err = rand.Read(secret)
+		signKey = secret
+	case jwt.SigningMethodRS256.Alg():
+		signKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case jwt.SigningMethodES256.Alg():
+		signKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return fmt.Errorf("unsupported algorithm %v", alg)
+	}
+	if err != nil {
+		return err
+	}
+	kid := make([]byte, 4)
+	rand.Read(kid)
+	ks := &jwtKeySet{Keys: []*jwtKey{{Kid: hex.EncodeToString(kid), Alg: alg, signKey: signKey}}}
+	if err := ks.init(); err != nil {
+		return err
+	}
+	return setJwtKeys(ks)
+}
+
+// GenerateJwtSecretKey signs and verifies tokens with a random HS256 key,
+// valid until the server restarts.
+func GenerateJwtSecretKey() {
+	GenerateJwtKey(jwt.SigningMethodHS256.Alg())
+}
+
+// LoadJwtKeyFile signs and verifies tokens with the keys of a JSON file,
+// which must not be accessible by group or others, nor the private key
+// files it refers to:
+//
+//	{"keys": [{"kid": "2021-01", "secret": "<base64>"},
+//	  {"kid": "2021-02", "alg": "ES256", "private_key_file": "<PEM file>"}]}
+func LoadJwtKeyFile(fileName string) error {
+	if err := checkPrivateFile(fileName); err != nil {
+		return fmt.Errorf("invalid JWT key file: %v", err)
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	ks := &jwtKeySet{}
+	if err := json.Unmarshal(data, ks); err != nil {
+		return fmt.Errorf("invalid JWT key file %v: %v", fileName, err)
+	}
+	if err := ks.init(); err != nil {
+		return fmt.Errorf("invalid JWT key file %v: %v", fileName, err)
+	}
+	return setJwtKeys(ks)
+}
+
+// WatchJwtKeyFile reloads the JWT key file when it changes, checking it
+// every interval until stop is closed.
+func WatchJwtKeyFile(fileName string, interval time.Duration, stop <-chan struct{}) {
+	files := func() []string { return []string{fileName} }
+	watchFiles(files, interval, stop, func() error {
+		if err := LoadJwtKeyFile(fileName); err != nil {
+			glog.Errorf("Failed to reload JWT keys: %v", err)
+			return err
+		}
+		glog.Infof("Reloaded JWT keys from %v", fileName)
+		return nil
+	})
+}
+
+// watchFiles calls load when any of the files changes, checking them every
+// interval until stop is closed. Failed loads are tried again at the next
+// interval.
+func watchFiles(files func() []string, interval time.Duration, stop <-chan struct{}, load func() error) {
+	modTimes := func() map[string]time.Time {
+		m := map[string]time.Time{}
+		for _, f := range files() {
+			if fi, err := os.Stat(f); err == nil {
+				m[f] = fi.ModTime()
+			}
+		}
+		return m
+	}
+	loaded := modTimes()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+		current := modTimes()
+		changed := len(current) != len(loaded)
+		for f, t := range current {
+			changed = changed || !t.Equal(loaded[f])
+		}
+		if changed && load() == nil {
+			loaded = modTimes()
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package gnmi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/golang/glog"
+)
+
+// JwtOidcConfig trusts the tokens of an OIDC identity provider. Their
+// signature is verified by the public keys of a local JWKS file, and the
+// values of a claim are mapped to SONiC roles.
+type JwtOidcConfig struct {
+	Issuer   string `json:"issuer"`
+	Audience string `json:"audience"`
+	JwksFile string `json:"jwks_file"`
+	// Claim of the user name, sub by default
+	UsernameClaim string `json:"username_claim"`
+	// Claim of the groups of the user, like groups
+	RolesClaim string `json:"roles_claim"`
+	// SONiC role of each group. Groups are roles when empty, otherwise
+	// unmapped groups are ignored.
+	RoleMap map[string]string `json:"role_map"`
+	// Longest lifetime of the accepted tokens, DefaultOidcMaxTokenLifetime
+	// by default. The revocations of users are kept as long.
+	MaxTokenLifetime string `json:"max_token_lifetime"`
+
+	keys        map[string]*jwk
+	maxLifetime time.Duration
+}
+
+// DefaultOidcMaxTokenLifetime is the longest lifetime of the accepted OIDC
+// tokens when the config sets none.
+const DefaultOidcMaxTokenLifetime = 24 * time.Hour
+
+var (
+	jwtOidcMu sync.RWMutex
+	jwtOidc   *JwtOidcConfig
+)
+
+// LoadJwtOidcConfig trusts the tokens of the OIDC issuer described by a JSON
+// file, along with the tokens of the server.
+func LoadJwtOidcConfig(fileName string) error {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	config := &JwtOidcConfig{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("invalid OIDC config %v: %v", fileName, err)
+	}
+	if err := config.init(); err != nil {
+		return fmt.Errorf("invalid OIDC config %v: %v", fileName, err)
+	}
+	jwtOidcMu.Lock()
+	defer jwtOidcMu.Unlock()
+	jwtOidc = config
+	return nil
+}
+
+// WatchJwtOidcConfig reloads the OIDC config and its JWKS file when they
+// change, checking them every interval until stop is closed.
+func WatchJwtOidcConfig(fileName string, interval time.Duration, stop <-chan struct{}) {
+	files := func() []string {
+		jwtOidcMu.RLock()
+		defer jwtOidcMu.RUnlock()
+		if jwtOidc == nil {
+			return []string{fileName}
+		}
+		return []string{fileName, jwtOidc.JwksFile}
+	}
+	watchFiles(files, interval, stop, func() error {
+		if err := LoadJwtOidcConfig(fileName); err != nil {
+			glog.Errorf("Failed to reload OIDC config: %v", err)
+			return err
+		}
+		glog.Infof("Reloaded OIDC config from %v", files())
+		return nil
+	})
+}
+
+func (c *JwtOidcConfig) init() error {
+	if c.Issuer == "" {
+		return fmt.Errorf("no issuer")
+	}
+	if c.Audience == "" {
+		return fmt.Errorf("no audience")
+	}
+	if c.RolesClaim == "" {
+		return fmt.Errorf("no roles_claim")
+	}
+	if c.UsernameClaim == "" {
+		c.UsernameClaim = "sub"
+	}
+	c.maxLifetime = DefaultOidcMaxTokenLifetime
+	if c.MaxTokenLifetime != "" {
+		lifetime, err := time.ParseDuration(c.MaxTokenLifetime)
+		if err != nil || lifetime <= 0 {
+			return fmt.Errorf("invalid max_token_lifetime %q", c.MaxTokenLifetime)
+		}
+		c.maxLifetime = lifetime
+	}
+	if c.JwksFile == "" {
+		return fmt.Errorf("no jwks_file")
+	}
+	data, err := ioutil.ReadFile(c.JwksFile)
+	if err != nil {
+		return err
+	}
+	var set struct {
+		Keys []*jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("invalid JWKS file %v: %v", c.JwksFile, err)
+	}
+	c.keys = make(map[string]*jwk)
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if _, err := k.publicKey(); err != nil {
+			return fmt.Errorf("invalid key %v of %v: %v", k.Kid, c.JwksFile, err)
+		}
+		c.keys[k.Kid] = k
+	}
+	if len(c.keys) == 0 {
+		return fmt.Errorf("no signing key in %v", c.JwksFile)
+	}
+	return nil
+}
+
+func jwkBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// publicKey returns the RSA or ECDSA public key of a JWK.
+func (k *jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := jwkBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid n: %v", err)
+		}
+		e, err := jwkBigInt(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, fmt.Errorf("invalid e")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %v", k.Crv)
+		}
+		x, err := jwkBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x: %v", err)
+		}
+		y, err := jwkBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y: %v", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point not on curve %v", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %v", k.Kty)
+}
+
+// keyFunc returns the key of the JWKS file verifying a token. The
+// algorithm of the token must match the key.
+func (c *JwtOidcConfig) keyFunc(token *jwt.Token) (interface{}, error) {
+	var k *jwk
+	if kid, ok := token.Header["kid"].(string); ok {
+		if k, ok = c.keys[kid]; !ok {
+			return nil, fmt.Errorf("unknown key %v", kid)
+		}
+	} else {
+		if len(c.keys) != 1 {
+			return nil, fmt.Errorf("no key ID")
+		}
+		for _, k = range c.keys {
+			break
+		}
+	}
+	if k.Alg != "" && k.Alg != token.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v", token.Method.Alg())
+	}
+	key, err := k.publicKey()
+	if err != nil {
+		return nil, err
+	}
+	switch method := token.Method.(type) {
+	case *jwt.SigningMethodRSA:
+		if _, ok := key.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+	case *jwt.SigningMethodECDSA:
+		if key, ok := key.(*ecdsa.PublicKey); ok && key.Curve.Params().BitSize == method.CurveBits {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected signing method %v", token.Method.Alg())
+}
+
+// claimStrings returns the values of a claim holding a string or a list of
+// strings.
+func claimStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func claimInt(v interface{}) int64 {
+	switch v := v.(type) {
+	case float64:
+		return int64(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	}
+	return 0
+}
+
+// parse validates a token of the issuer and returns its user and roles.
+func (c *JwtOidcConfig) parse(tokenString string) (*Claims, error) {
+	mc := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, mc, c.keyFunc); err != nil {
+		return nil, err
+	}
+	if !mc.VerifyIssuer(c.Issuer, true) {
+		return nil, fmt.Errorf("unexpected issuer %v", mc["iss"])
+	}
+	audience := false
+	for _, aud := range claimStrings(mc["aud"]) {
+		audience = audience || aud == c.Audience
+	}
+	if !audience {
+		return nil, fmt.Errorf("unexpected audience %v", mc["aud"])
+	}
+	if _, ok := mc["exp"]; !ok {
+		return nil, fmt.Errorf("token without expiry")
+	}
+	issued := time.Now()
+	if _, ok := mc["iat"]; ok {
+		issued = time.Unix(claimInt(mc["iat"]), 0)
+	}
+	if time.Unix(claimInt(mc["exp"]), 0).Sub(issued) > c.maxLifetime {
+		return nil, fmt.Errorf("token lifetime longer than %v", c.maxLifetime)
+	}
+
+	claims := &Claims{StandardClaims: jwt.StandardClaims{
+		Issuer:    c.Issuer,
+		ExpiresAt: claimInt(mc["exp"]),
+		IssuedAt:  claimInt(mc["iat"]),
+	}}
+	claims.Id, _ = mc["jti"].(string)
+	claims.Username, _ = mc[c.UsernameClaim].(string)
+	if claims.Username == "" {
+		return nil, fmt.Errorf("no %v claim", c.UsernameClaim)
+	}
+	for _, group := range claimStrings(mc[c.RolesClaim]) {
+		if len(c.RoleMap) == 0 {
+			claims.Roles = append(claims.Roles, group)
+		} else if role, ok := c.RoleMap[group]; ok {
+			claims.Roles = append(claims.Roles, role)
+		}
+	}
+	if len(claims.Roles) == 0 {
+		return nil, fmt.Errorf("user %v has no SONiC role", claims.Username)
+	}
+	return claims, nil
+}
+
+// jwtMaxTokenLifetime returns the longest lifetime of the accepted tokens,
+// of the server or of the OIDC issuer.
+func jwtMaxTokenLifetime() time.Duration {
+	jwtOidcMu.RLock()
+	defer jwtOidcMu.RUnlock()
+	if jwtOidc != nil && jwtOidc.maxLifetime > JwtValidInt {
+		return jwtOidc.maxLifetime
+	}
+	return JwtValidInt
+}
+
+// parseJwtToken validates a token issued by the server or by the trusted
+// OIDC issuer, and returns its claims. Only the claims of the tokens of the
+// OIDC issuer have an Issuer.
+func parseJwtToken(tokenString string) (*Claims, error) {
+	jwtOidcMu.RLock()
+	oidc := jwtOidc
+	jwtOidcMu.RUnlock()
+	if oidc != nil {
+		mc := jwt.MapClaims{}
+		if _, _, err := new(jwt.Parser).ParseUnverified(tokenString, mc); err == nil && mc["iss"] == oidc.Issuer {
+			return oidc.parse(tokenString)
+		}
+	}
+
+	claims := &Claims{}
+	tkn, err := jwt.ParseWithClaims(tokenString, claims, jwtKeyFunc)
+	if err != nil {
+		return nil, err
+	}
+	if !tkn.Valid || claims.Issuer != "" {
+		return nil, fmt.Errorf("Invalid JWT Token")
+	}
+	return claims, nil
+}
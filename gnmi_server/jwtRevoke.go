@@ -0,0 +1,163 @@
+package gnmi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	sdc "github.com/Azure/sonic-telemetry/sonic_data_client"
+	"github.com/golang/glog"
+)
+
+const (
+	// JwtRevokedTokenTable is the STATE_DB table of the revoked tokens,
+	// keyed by token ID.
+	JwtRevokedTokenTable = "JWT_REVOKED_TOKEN"
+	// JwtRevokedUserTable is the STATE_DB table of the users whose tokens
+	// issued before a time are revoked.
+	JwtRevokedUserTable = "JWT_REVOKED_USER"
+)
+
+// JwtDenylistDb persists the revocations in STATE_DB when set, so that they
+// survive restarts.
+var JwtDenylistDb bool
+
+// jwtDenylist holds the revoked tokens until they expire.
+type jwtDenylist struct {
+	mu sync.Mutex
+	// Expiry of the revoked tokens by token ID
+	tokens map[string]time.Time
+	// Time the tokens of the users were revoked at
+	users map[string]time.Time
+}
+
+var jwtRevoked = &jwtDenylist{
+	tokens: make(map[string]time.Time),
+	users:  make(map[string]time.Time),
+}
+
+// prune forgets the revocations of the tokens that expired. The revocations
+// of users are kept for the longest lifetime of the accepted tokens.
+func (d *jwtDenylist) prune(now time.Time) {
+	for id, expires := range d.tokens {
+		if now.After(expires) {
+			delete(d.tokens, id)
+		}
+	}
+	lifetime := jwtMaxTokenLifetime()
+	for user, before := range d.users {
+		if now.After(before.Add(lifetime)) {
+			delete(d.users, user)
+		}
+	}
+}
+
+func (d *jwtDenylist) revokeToken(id string, expires time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.prune(time.Now())
+	d.tokens[id] = expires
+}
+
+func (d *jwtDenylist) revokeUser(user string, before time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.prune(time.Now())
+	if before.After(d.users[user]) {
+		d.users[user] = before
+	}
+}
+
+// revoked tells whether the token of claims was revoked, by its ID or with
+// all the tokens of its user. The tokens without sub-second issue time, like
+// the OIDC ones, issued in the second of the revocation of their user are
+// not revoked, for the tokens issued right after it to be accepted.
+func (d *jwtDenylist) revoked(claims *Claims) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.tokens[claims.Id]; ok && claims.Id != "" {
+		return true
+	}
+	before, ok := d.users[claims.Username]
+	if !ok {
+		return false
+	}
+	if claims.IssuedAtNs != 0 {
+		return claims.IssuedAtNs <= before.UnixNano()
+	}
+	return claims.IssuedAt < before.Unix()
+}
+
+// RevokeJwtToken rejects the token of claims until it expires.
+func RevokeJwtToken(claims *Claims) error {
+	expires := time.Unix(claims.ExpiresAt, 0)
+	jwtRevoked.revokeToken(claims.Id, expires)
+	if !JwtDenylistDb {
+		return nil
+	}
+	return sdc.SetDbTableEntry("STATE_DB", JwtRevokedTokenTable, claims.Id, map[string]string{
+		"username":   claims.Username,
+		"expires_at": strconv.FormatInt(claims.ExpiresAt, 10),
+	}, expires)
+}
+
+// RevokeJwtUser rejects the tokens issued to a user so far.
+func RevokeJwtUser(user string) error {
+	before := time.Now()
+	jwtRevoked.revokeUser(user, before)
+	if !JwtDenylistDb {
+		return nil
+	}
+	return sdc.SetDbTableEntry("STATE_DB", JwtRevokedUserTable, user, map[string]string{
+		"revoked_at":    strconv.FormatInt(before.Unix(), 10),
+		"revoked_at_ns": strconv.FormatInt(before.UnixNano(), 10),
+	}, before.Add(jwtMaxTokenLifetime()))
+}
+
+// LoadJwtDenylistDb reads the revocations persisted in STATE_DB.
+func LoadJwtDenylistDb() error {
+	tokens, err := sdc.GetDbTable("STATE_DB", JwtRevokedTokenTable)
+	if err != nil {
+		return err
+	}
+	for id, fv := range tokens {
+		expiresAt, err := strconv.ParseInt(fv["expires_at"], 10, 64)
+		if err != nil {
+			glog.Warningf("Invalid revoked token %v: %v", id, err)
+			continue
+		}
+		jwtRevoked.revokeToken(id, time.Unix(expiresAt, 0))
+	}
+	users, err := sdc.GetDbTable("STATE_DB", JwtRevokedUserTable)
+	if err != nil {
+		return err
+	}
+	for user, fv := range users {
+		if ns, ok := fv["revoked_at_ns"]; ok {
+			revokedAt, err := strconv.ParseInt(ns, 10, 64)
+			if err != nil {
+				glog.Warningf("Invalid revoked user %v: %v", user, err)
+				continue
+			}
+			jwtRevoked.revokeUser(user, time.Unix(0, revokedAt))
+			continue
+		}
+		revokedAt, err := strconv.ParseInt(fv["revoked_at"], 10, 64)
+		if err != nil {
+			glog.Warningf("Invalid revoked user %v: %v", user, err)
+			continue
+		}
+		jwtRevoked.revokeUser(user, time.Unix(revokedAt, 0))
+	}
+	glog.Infof("Loaded %v revoked tokens and %v revoked users from STATE_DB", len(tokens), len(users))
+	return nil
+}
+
+// jwtTokenId returns a new random token ID.
+func jwtTokenId() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
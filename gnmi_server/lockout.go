@@ -0,0 +1,294 @@
+package gnmi
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Azure/sonic-telemetry/audit"
+	"github.com/Azure/sonic-telemetry/common_utils"
+	spb_admin "github.com/Azure/sonic-telemetry/proto/gnoi/admin"
+	log "github.com/golang/glog"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// AuthLockoutPolicy locks out the users and the source addresses failing
+// password authentication too many times.
+type AuthLockoutPolicy struct {
+	// Failures allowed before a lockout, 0 disables lockouts
+	MaxFailures int
+	// Duration of the first lockout, doubled by each failure after it
+	Duration time.Duration
+	// Longest lockout. Failures are forgotten after that long without
+	// failure.
+	MaxDuration time.Duration
+}
+
+// lockoutKey is a user or a source address whose failures are counted.
+type lockoutKey struct {
+	kind string
+	name string
+}
+
+type lockoutState struct {
+	failures int
+	// Attempts checked and not recorded yet
+	inflight    int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// maxLockoutStates bounds the users and addresses whose attempts are
+// counted, against floods of attempts with random user names.
+var maxLockoutStates = 10000
+
+// authLockout counts the password authentication failures.
+type authLockout struct {
+	mu     sync.Mutex
+	policy AuthLockoutPolicy
+	audit  *audit.Logger
+	states map[lockoutKey]*lockoutState
+}
+
+var authLockouts = &authLockout{states: map[lockoutKey]*lockoutState{}}
+
+// SetAuthLockout sets the lockout policy of password authentication. The
+// lockouts are audited by logger, unless it is nil.
+func SetAuthLockout(policy AuthLockoutPolicy, logger *audit.Logger) {
+	authLockouts.mu.Lock()
+	defer authLockouts.mu.Unlock()
+	authLockouts.policy = policy
+	authLockouts.audit = logger
+	authLockouts.states = map[lockoutKey]*lockoutState{}
+}
+
+// peerAddress returns the source address of the client of ctx, without its
+// port.
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+func lockoutKeys(ctx context.Context, username string) []lockoutKey {
+	keys := []lockoutKey{{"user", username}}
+	if addr := peerAddress(ctx); addr != "" {
+		keys = append(keys, lockoutKey{"address", addr})
+	}
+	return keys
+}
+
+// prune forgets the failures that are not counted anymore.
+func (l *authLockout) prune(now time.Time) {
+	for key, st := range l.states {
+		if st.inflight == 0 && now.After(st.lockedUntil) && now.Sub(st.last) > l.policy.MaxDuration {
+			delete(l.states, key)
+		}
+	}
+}
+
+// state returns the state of key, adding it if needed. When maxLockoutStates
+// are counted, the state of a user failing least recently, neither locked
+// out nor in flight, is replaced. Addresses, harder to vary than user names,
+// are only replaced by addresses, when no user can be. nil is returned if
+// there is no state to replace.
+func (l *authLockout) state(key lockoutKey, now time.Time) *lockoutState {
+	if st, ok := l.states[key]; ok {
+		return st
+	}
+	if len(l.states) >= maxLockoutStates {
+		l.prune(now)
+	}
+	if len(l.states) >= maxLockoutStates {
+		var oldest lockoutKey
+		var found *lockoutState
+		for k, st := range l.states {
+			if st.inflight > 0 || now.Before(st.lockedUntil) {
+				continue
+			}
+			if k.kind == "address" && key.kind != "address" {
+				continue
+			}
+			if found == nil || (oldest.kind == k.kind && st.last.Before(found.last)) ||
+				(oldest.kind == "address" && k.kind == "user") {
+				oldest, found = k, st
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		delete(l.states, oldest)
+	}
+	st := &lockoutState{}
+	l.states[key] = st
+	return st
+}
+
+// concurrent returns the attempts of a key allowed in flight: the failures
+// left before a lockout, or one after a lockout.
+func (l *authLockout) concurrent(st *lockoutState) int {
+	if left := l.policy.MaxFailures - st.failures; left > 1 {
+		return left
+	}
+	return 1
+}
+
+// check fails if the user or the address of the client of ctx are locked
+// out, or have as many attempts in flight as failures left before a
+// lockout, or if they cannot be counted. Otherwise the attempt is counted
+// in flight until it is recorded.
+func (l *authLockout) check(ctx context.Context, username string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.policy.MaxFailures == 0 {
+		return nil
+	}
+	now := time.Now()
+	keys := lockoutKeys(ctx, username)
+	var wait time.Duration
+	busy := false
+	for _, key := range keys {
+		st, ok := l.states[key]
+		if !ok {
+			continue
+		}
+		if st.lockedUntil.Sub(now) > wait {
+			wait = st.lockedUntil.Sub(now)
+		}
+		busy = busy || st.inflight >= l.concurrent(st)
+	}
+	if wait > 0 {
+		return status.Errorf(codes.ResourceExhausted, "too many failed attempts, retry in %v", wait.Round(time.Second))
+	}
+	if busy {
+		return status.Errorf(codes.ResourceExhausted, "too many attempts in progress, retry later")
+	}
+	var counted []*lockoutState
+	for _, key := range keys {
+		st := l.state(key, now)
+		if st == nil {
+			for _, st := range counted {
+				st.inflight--
+			}
+			log.V(2).Infof("Password attempt of %v rejected, %v users and addresses counted", username, len(l.states))
+			return status.Errorf(codes.ResourceExhausted, "too many attempts in progress, retry later")
+		}
+		st.inflight++
+		counted = append(counted, st)
+	}
+	return nil
+}
+
+// record counts a password authentication of the user from the address of
+// the client of ctx, checked before. Successes reset the failures of the
+// user only, the ones of the address are forgotten with time.
+func (l *authLockout) record(ctx context.Context, username string, success bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.policy.MaxFailures == 0 {
+		return
+	}
+	keys := lockoutKeys(ctx, username)
+	for _, key := range keys {
+		if st, ok := l.states[key]; ok && st.inflight > 0 {
+			st.inflight--
+		}
+	}
+	if success {
+		if st, ok := l.states[keys[0]]; ok {
+			st.failures = 0
+			st.lockedUntil = time.Time{}
+			if st.inflight == 0 {
+				delete(l.states, keys[0])
+			}
+		}
+		return
+	}
+
+	now := time.Now()
+	l.prune(now)
+	for _, key := range keys {
+		st := l.state(key, now)
+		if st == nil {
+			continue
+		}
+		st.failures++
+		st.last = now
+		if st.failures < l.policy.MaxFailures {
+			continue
+		}
+		d := l.policy.Duration
+		for i := l.policy.MaxFailures; i < st.failures && d < l.policy.MaxDuration; i++ {
+			d *= 2
+		}
+		if d > l.policy.MaxDuration {
+			d = l.policy.MaxDuration
+		}
+		st.lockedUntil = now.Add(d)
+		l.audited(ctx, key, st, d)
+	}
+}
+
+// audited logs and audits the lockout of a key.
+func (l *authLockout) audited(ctx context.Context, key lockoutKey, st *lockoutState, d time.Duration) {
+	rc, _ := common_utils.GetContext(ctx)
+	msg := fmt.Sprintf("%v %v locked out for %v after %v failed password attempts", key.kind, key.name, d, st.failures)
+	log.Warningf("[%s] %s", rc.ID, msg)
+	if l.audit == nil {
+		return
+	}
+	method, _ := grpc.Method(ctx)
+	r := &audit.Record{
+		Time:      time.Now(),
+		Operation: method,
+		Event:     "lockout",
+		RequestID: rc.ID,
+		Code:      codes.ResourceExhausted.String(),
+		Error:     msg,
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		r.Peer = p.Addr.String()
+	}
+	if key.kind == "user" {
+		r.User = key.name
+	}
+	l.audit.Log(r)
+}
+
+// list returns the users and addresses having failed password
+// authentication recently.
+func (l *authLockout) list() []*spb_admin.Lockout {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(time.Now())
+	lockouts := make([]*spb_admin.Lockout, 0, len(l.states))
+	for key, st := range l.states {
+		if st.failures == 0 {
+			continue
+		}
+		lo := &spb_admin.Lockout{Kind: key.kind, Name: key.name, Failures: int64(st.failures)}
+		if !st.lockedUntil.IsZero() {
+			lo.LockedUntil = st.lockedUntil.UnixNano()
+		}
+		lockouts = append(lockouts, lo)
+	}
+	sort.Slice(lockouts, func(i, j int) bool {
+		if lockouts[i].Kind != lockouts[j].Kind {
+			return lockouts[i].Kind > lockouts[j].Kind
+		}
+		return lockouts[i].Name < lockouts[j].Name
+	})
+	return lockouts
+}
@@ -0,0 +1,130 @@
+package gnmi
+
+import (
+	"sort"
+
+	"github.com/Azure/sonic-telemetry/metrics"
+	spb_admin "github.com/Azure/sonic-telemetry/proto/gnoi/admin"
+)
+
+var authAttempts = metrics.NewCounter("telemetry_auth_attempts_total",
+	"Authentication attempts by mechanism and result.", "mechanism", "result")
+
+func countAuth(mechanism string, success bool) {
+	if success {
+		authAttempts.Inc(mechanism, "success")
+	} else {
+		authAttempts.Inc(mechanism, "failure")
+	}
+}
+
+// sessionKey groups the sessions by subscription mode and target.
+type sessionKey struct {
+	mode   string
+	target string
+}
+
+// sessionStats are the counters of the sessions of a group.
+type sessionStats struct {
+	sessions   int
+	sent       int64
+	recv       int64
+	errors     int64
+	queueDepth int64
+	dropped    int64
+	conflated  int64
+}
+
+func (st *sessionStats) add(s *spb_admin.Session) {
+	st.sent += s.SentMsgs
+	st.recv += s.RecvMsgs
+	st.errors += s.Errors
+	st.dropped += s.Dropped
+	st.conflated += s.Conflated
+}
+
+// closeSession removes a closed Subscribe client from the server and keeps
+// its counters, so that the message counters of the server never decrease.
+// Both are done in the same critical section for Collect to count the client
+// exactly once.
+func (srv *Server) closeSession(c *Client) {
+	srv.cMu.Lock()
+	defer srv.cMu.Unlock()
+	if srv.clients[c.String()] == c {
+		delete(srv.clients, c.String())
+	}
+	s := c.session()
+	key := sessionKey{s.Mode, s.Target}
+	st, ok := srv.closed[key]
+	if !ok {
+		st = &sessionStats{}
+		srv.closed[key] = st
+	}
+	st.add(s)
+}
+
+// Collect writes the metrics of the Subscribe sessions of the server.
+func (srv *Server) Collect(w *metrics.Writer) {
+	stats := map[sessionKey]*sessionStats{}
+	srv.cMu.Lock()
+	clients := make([]*Client, 0, len(srv.clients))
+	for _, c := range srv.clients {
+		clients = append(clients, c)
+	}
+	for key, st := range srv.closed {
+		closed := *st
+		stats[key] = &closed
+	}
+	srv.cMu.Unlock()
+
+	for _, c := range clients {
+		s := c.session()
+		key := sessionKey{s.Mode, s.Target}
+		st, ok := stats[key]
+		if !ok {
+			st = &sessionStats{}
+			stats[key] = st
+		}
+		st.sessions++
+		st.queueDepth += s.QueueDepth
+		st.add(s)
+	}
+
+	keys := make([]sessionKey, 0, len(stats))
+	for key := range stats {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].mode != keys[j].mode {
+			return keys[i].mode < keys[j].mode
+		}
+		return keys[i].target < keys[j].target
+	})
+
+	for _, m := range []struct {
+		name  string
+		help  string
+		typ   string
+		value func(st *sessionStats) float64
+	}{
+		{"telemetry_sessions", "Active Subscribe sessions.", "gauge",
+			func(st *sessionStats) float64 { return float64(st.sessions) }},
+		{"telemetry_messages_sent_total", "Messages sent to Subscribe clients.", "counter",
+			func(st *sessionStats) float64 { return float64(st.sent) }},
+		{"telemetry_messages_received_total", "Messages received from Subscribe clients.", "counter",
+			func(st *sessionStats) float64 { return float64(st.recv) }},
+		{"telemetry_session_errors_total", "Errors of Subscribe sessions.", "counter",
+			func(st *sessionStats) float64 { return float64(st.errors) }},
+		{"telemetry_queue_depth", "Messages waiting to be sent to Subscribe clients.", "gauge",
+			func(st *sessionStats) float64 { return float64(st.queueDepth) }},
+		{"telemetry_queue_dropped_total", "Updates dropped from full send queues.", "counter",
+			func(st *sessionStats) float64 { return float64(st.dropped) }},
+		{"telemetry_queue_conflated_total", "Updates conflated in full send queues.", "counter",
+			func(st *sessionStats) float64 { return float64(st.conflated) }},
+	} {
+		w.Family(m.name, m.help, m.typ)
+		for _, key := range keys {
+			w.Sample(m.name, m.value(stats[key]), "mode", key.mode, "target", key.target)
+		}
+	}
+}
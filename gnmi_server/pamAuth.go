@@ -5,7 +5,6 @@ import (
 	"errors"
 	"github.com/golang/glog"
 	"github.com/msteinert/pam"
-	"golang.org/x/crypto/ssh"
 	"os/user"
 )
 
@@ -84,29 +83,23 @@ func PopulateAuthStruct(username string, auth *common_utils.AuthInfo, r []string
 	return nil
 }
 
+// UserPwAuth checks the password of a user with the password backend, or
+// the cache of the successful checks.
 func UserPwAuth(username string, passwd string) (bool, error) {
-	/*
-	 * mgmt-framework container does not have access to /etc/passwd, /etc/group,
-	 * /etc/shadow and /etc/tacplus_conf files of host. One option is to share
-	 * /etc of host with /etc of container. For now disable this and use ssh
-	 * for authentication.
-	 */
-	// err := PAMAuthUser(username, passwd)
-
-	//Use ssh for authentication.
-	config := &ssh.ClientConfig{
-		User: username,
-		Auth: []ssh.AuthMethod{
-			ssh.Password(passwd),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	backend, cache := passwordAuth()
+	if r, ok := backend.(passwordReloader); ok && r.reload() && cache != nil {
+		// The passwords cached may have been changed or removed.
+		cache.clear()
 	}
-	c, err := ssh.Dial("tcp", "127.0.0.1:22", config)
-	if err != nil {
+	if cache != nil && cache.lookup(username, passwd) {
+		return true, nil
+	}
+	if err := backend.Authenticate(username, passwd); err != nil {
 		glog.Infof("Authentication failed. user=%s, error:%s", username, err.Error())
 		return false, err
 	}
-	defer c.Conn.Close()
-
+	if cache != nil {
+		cache.add(username, passwd)
+	}
 	return true, nil
 }
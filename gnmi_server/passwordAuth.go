@@ -0,0 +1,244 @@
+package gnmi
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/ssh"
+)
+
+// PasswordBackend checks the passwords of users.
+type PasswordBackend interface {
+	Authenticate(username, password string) error
+}
+
+// passwordReloader is implemented by the backends reading the passwords
+// from a file. reload reloads the file if it changed, and reports whether
+// it did, so that the checks cached before can be forgotten.
+type passwordReloader interface {
+	reload() bool
+}
+
+// PasswordBackendNames are the backends NewPasswordBackend creates.
+var PasswordBackendNames = []string{"ssh", "pam", "htpasswd"}
+
+// NewPasswordBackend creates the password backend of a name: ssh, which
+// logs in to the local sshd, pam, or htpasswd, which checks the bcrypt
+// hashes of a file.
+func NewPasswordBackend(name string, fileName string) (PasswordBackend, error) {
+	switch name {
+	case "ssh":
+		return &sshBackend{addr: "127.0.0.1:22"}, nil
+	case "pam":
+		return pamBackend{}, nil
+	case "htpasswd":
+		if fileName == "" {
+			return nil, fmt.Errorf("htpasswd password backend requires a file")
+		}
+		b := &htpasswdBackend{fileName: fileName}
+		if err := b.load(); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("unknown password backend %v, expected one of %v", name, PasswordBackendNames)
+}
+
+// sshBackend checks passwords by opening an SSH connection.
+type sshBackend struct {
+	addr string
+}
+
+func (b *sshBackend) Authenticate(username, password string) error {
+	config := &ssh.ClientConfig{
+		User: username,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(password),
+		},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	}
+	c, err := ssh.Dial("tcp", b.addr, config)
+	if err != nil {
+		return err
+	}
+	c.Conn.Close()
+	return nil
+}
+
+// pamBackend checks passwords with the login PAM service.
+type pamBackend struct{}
+
+func (pamBackend) Authenticate(username, password string) error {
+	return PAMAuthUser(username, password)
+}
+
+// htpasswdBackend checks passwords against the bcrypt hashes of an
+// htpasswd file, reloaded when it changes.
+type htpasswdBackend struct {
+	fileName string
+
+	mu      sync.Mutex
+	modTime time.Time
+	hashes  map[string][]byte
+}
+
+// htpasswdDummyHash is compared to the passwords of unknown users, so that
+// they take as long to reject as the ones of known users.
+var (
+	htpasswdDummyOnce sync.Once
+	htpasswdDummyHash []byte
+)
+
+func (b *htpasswdBackend) load() error {
+	fi, err := os.Stat(b.fileName)
+	if err != nil {
+		return err
+	}
+	if fi.ModTime().Equal(b.modTime) {
+		return nil
+	}
+	f, err := os.Open(b.fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hashes := map[string][]byte{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.Index(line, ":")
+		if i <= 0 {
+			return fmt.Errorf("%v:%d: expected user:hash", b.fileName, n)
+		}
+		hash := []byte(line[i+1:])
+		if _, err := bcrypt.Cost(hash); err != nil {
+			return fmt.Errorf("%v:%d: unsupported hash of user %v, only bcrypt is supported", b.fileName, n, line[:i])
+		}
+		hashes[line[:i]] = hash
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	b.hashes = hashes
+	b.modTime = fi.ModTime()
+	return nil
+}
+
+func (b *htpasswdBackend) reload() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	modTime := b.modTime
+	if err := b.load(); err != nil {
+		glog.Errorf("Failed to reload %v, keeping the users loaded: %v", b.fileName, err)
+	}
+	return !b.modTime.Equal(modTime)
+}
+
+func (b *htpasswdBackend) Authenticate(username, password string) error {
+	b.reload()
+	b.mu.Lock()
+	hash, ok := b.hashes[username]
+	b.mu.Unlock()
+	if !ok {
+		htpasswdDummyOnce.Do(func() {
+			htpasswdDummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy"), bcrypt.DefaultCost)
+		})
+		bcrypt.CompareHashAndPassword(htpasswdDummyHash, []byte(password))
+		return fmt.Errorf("unknown user %v", username)
+	}
+	return bcrypt.CompareHashAndPassword(hash, []byte(password))
+}
+
+// passwordCache remembers the successful password checks for a while.
+// Passwords are kept as salted hashes.
+type passwordCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	salt    []byte
+	entries map[string]passwordCacheEntry
+}
+
+type passwordCacheEntry struct {
+	hash    []byte
+	expires time.Time
+}
+
+func newPasswordCache(ttl time.Duration) *passwordCache {
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	return &passwordCache{ttl: ttl, salt: salt, entries: map[string]passwordCacheEntry{}}
+}
+
+func (pc *passwordCache) hash(username, password string) []byte {
+	h := sha256.New()
+	h.Write(pc.salt)
+	h.Write([]byte(username))
+	h.Write([]byte{0})
+	h.Write([]byte(password))
+	return h.Sum(nil)
+}
+
+func (pc *passwordCache) lookup(username, password string) bool {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	e, ok := pc.entries[username]
+	if !ok || time.Now().After(e.expires) {
+		return false
+	}
+	return subtle.ConstantTimeCompare(e.hash, pc.hash(username, password)) == 1
+}
+
+func (pc *passwordCache) clear() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.entries = map[string]passwordCacheEntry{}
+}
+
+func (pc *passwordCache) add(username, password string) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	now := time.Now()
+	for u, e := range pc.entries {
+		if now.After(e.expires) {
+			delete(pc.entries, u)
+		}
+	}
+	pc.entries[username] = passwordCacheEntry{hash: pc.hash(username, password), expires: now.Add(pc.ttl)}
+}
+
+var (
+	passwordMu      sync.RWMutex
+	passwordBackend PasswordBackend = &sshBackend{addr: "127.0.0.1:22"}
+	passwordChecks  *passwordCache
+)
+
+// SetPasswordAuth checks the passwords with a backend, remembering the
+// successful checks for ttl, unless it is 0.
+func SetPasswordAuth(backend PasswordBackend, ttl time.Duration) {
+	passwordMu.Lock()
+	defer passwordMu.Unlock()
+	passwordBackend = backend
+	passwordChecks = nil
+	if ttl > 0 {
+		passwordChecks = newPasswordCache(ttl)
+	}
+}
+
+func passwordAuth() (PasswordBackend, *passwordCache) {
+	passwordMu.RLock()
+	defer passwordMu.RUnlock()
+	return passwordBackend, passwordChecks
+}
@@ -0,0 +1,130 @@
+package gnmi
+
+import (
+	"fmt"
+	"net"
+	"os/user"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/Azure/sonic-telemetry/common_utils"
+	"github.com/golang/glog"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// PeerCredInfo is the AuthInfo of the clients of the unix socket listener,
+// read from the SO_PEERCRED option of their connection.
+type PeerCredInfo struct {
+	credentials.CommonAuthInfo
+	Pid int32
+	Uid uint32
+	Gid uint32
+}
+
+func (PeerCredInfo) AuthType() string {
+	return "peercred"
+}
+
+// peerCredentials are the transport credentials of the unix socket
+// listener: no TLS, the peer process is identified by its credentials.
+type peerCredentials struct{}
+
+// Number of connections accepted, telling apart the peer addresses.
+var peerCredConns uint64
+
+// peerCredConn reports the peer process as remote address, unix socket
+// clients having no address of their own.
+type peerCredConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c *peerCredConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+func (peerCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected %T connection on unix socket", conn)
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return nil, nil, err
+	}
+	var cred *syscall.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err == nil {
+		err = credErr
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read peer credentials: %v", err)
+	}
+
+	info := PeerCredInfo{
+		CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity},
+		Pid:            cred.Pid,
+		Uid:            cred.Uid,
+		Gid:            cred.Gid,
+	}
+	n := atomic.AddUint64(&peerCredConns, 1)
+	addr := &net.UnixAddr{Net: "unix", Name: fmt.Sprintf("pid=%d,uid=%d,conn=%d", cred.Pid, cred.Uid, n)}
+	return &peerCredConn{Conn: conn, addr: addr}, info, nil
+}
+
+func (peerCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, fmt.Errorf("peer credentials are for servers only")
+}
+
+func (peerCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "peercred"}
+}
+
+func (c peerCredentials) Clone() credentials.TransportCredentials {
+	return c
+}
+
+func (peerCredentials) OverrideServerName(string) error {
+	return nil
+}
+
+func isPeerCredConn(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	_, ok = p.AuthInfo.(PeerCredInfo)
+	return ok
+}
+
+// PeerCredAuthenAndAuthor authenticates a unix socket client as the user
+// owning its process.
+func PeerCredAuthenAndAuthor(ctx context.Context) (context.Context, error) {
+	rc, ctx := common_utils.GetContext(ctx)
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx, status.Error(codes.Unauthenticated, "no peer found")
+	}
+	info, ok := p.AuthInfo.(PeerCredInfo)
+	if !ok {
+		return ctx, status.Error(codes.Unauthenticated, "unexpected peer transport credentials")
+	}
+	usr, err := user.LookupId(strconv.FormatUint(uint64(info.Uid), 10))
+	if err != nil {
+		glog.Infof("[%s] Failed to look up uid %d of pid %d; %v", rc.ID, info.Uid, info.Pid, err)
+		return ctx, status.Errorf(codes.Unauthenticated, "unknown peer uid %d", info.Uid)
+	}
+	if err := PopulateAuthStruct(usr.Username, &rc.Auth, nil); err != nil {
+		glog.Infof("[%s] Failed to retrieve authentication information; %v", rc.ID, err)
+		return ctx, status.Errorf(codes.Unauthenticated, "")
+	}
+	return ctx, nil
+}
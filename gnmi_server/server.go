@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Azure/sonic-mgmt-common/translib"
+	"github.com/Azure/sonic-telemetry/audit"
 	"github.com/Azure/sonic-telemetry/common_utils"
 	spb "github.com/Azure/sonic-telemetry/proto"
 	spb_gnoi "github.com/Azure/sonic-telemetry/proto/gnoi"
+	spb_admin "github.com/Azure/sonic-telemetry/proto/gnoi/admin"
 	spb_jwt_gnoi "github.com/Azure/sonic-telemetry/proto/gnoi/jwt"
 	sdc "github.com/Azure/sonic-telemetry/sonic_data_client"
 	log "github.com/golang/glog"
@@ -22,23 +24,36 @@ import (
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 	"net"
+	"os"
 	"strings"
 	"sync"
+	"time"
 )
 
 var (
-	supportedEncodings = []gnmipb.Encoding{gnmipb.Encoding_JSON, gnmipb.Encoding_JSON_IETF}
+	supportedEncodings = []gnmipb.Encoding{gnmipb.Encoding_JSON, gnmipb.Encoding_JSON_IETF, gnmipb.Encoding_PROTO}
 )
 
 // Server manages a single gNMI Server implementation. Each client that connects
 // via Subscribe or Get will receive a stream of updates based on the requested
 // path. Set request is processed by server too.
 type Server struct {
-	s       *grpc.Server
-	lis     net.Listener
+	s   *grpc.Server
+	lis net.Listener
+	// Additional listeners of s
+	extraLis []net.Listener
+	// Server of the unix socket listener, without TLS
+	us      *grpc.Server
+	ulis    net.Listener
 	config  *Config
 	cMu     sync.Mutex
 	clients map[string]*Client
+	// Counters of the closed Subscribe sessions
+	closed map[sessionKey]*sessionStats
+	// Set once the server is shutting down
+	stopping bool
+	// Protects the config settings changed at runtime
+	settingsMu sync.RWMutex
 }
 type AuthTypes map[string]bool
 
@@ -46,8 +61,25 @@ type AuthTypes map[string]bool
 type Config struct {
 	// Port for the Server to listen on. If 0 or unset the Server will pick a port
 	// for this Server.
-	Port     int64
-	UserAuth AuthTypes
+	Port int64
+	// Addrs are the addresses to listen on, like "10.1.0.32:8080" or
+	// "[fc00::1]:8080". If empty, the Server listens on Port of all the
+	// interfaces.
+	Addrs []string
+	// UnixSocket is the path of an additional unix socket listener. Its
+	// clients do not use TLS and are identified by their peer credentials.
+	UnixSocket string
+	UserAuth   AuthTypes
+	// AuthzPolicy authorizes the paths of Get, Set and Subscribe requests
+	// by user roles. No authorization is done if it is nil.
+	AuthzPolicy *AuthzPolicy
+	// Audit records all the RPCs served. No audit is done if it is nil.
+	Audit *audit.Logger
+	// QueueLimit bounds the number of updates waiting to be sent to each
+	// Subscribe client, 0 means no limit. QueuePolicy applies to the
+	// updates in excess.
+	QueueLimit  int
+	QueuePolicy QueuePolicy
 }
 
 var AuthLock sync.Mutex
@@ -87,7 +119,12 @@ func (i AuthTypes) Enabled(mode string) bool {
 	return false
 }
 
+// Set replaces the enabled modes with the comma separated modes, so that
+// the last setting of the flag, like the CONFIG_DB one, takes precedence.
 func (i AuthTypes) Set(mode string) error {
+	for m := range i {
+		i[m] = false
+	}
 	modes := strings.Split(mode, ",")
 	for _, m := range modes {
 		m = strings.Trim(m, " ")
@@ -122,41 +159,158 @@ func NewServer(config *Config, opts []grpc.ServerOption) (*Server, error) {
 		return nil, errors.New("config not provided")
 	}
 
+	opts = append(append([]grpc.ServerOption{}, opts...), auditServerOptions(config)...)
 	s := grpc.NewServer(opts...)
-	reflection.Register(s)
 
 	srv := &Server{
 		s:       s,
 		config:  config,
 		clients: map[string]*Client{},
+		closed:  map[sessionKey]*sessionStats{},
 	}
-	var err error
 	if srv.config.Port < 0 {
 		srv.config.Port = 0
 	}
-	srv.lis, err = net.Listen("tcp", fmt.Sprintf(":%d", srv.config.Port))
-	if err != nil {
-		return nil, fmt.Errorf("failed to open listener port %d: %v", srv.config.Port, err)
+	addrs := srv.config.Addrs
+	if len(addrs) == 0 {
+		addrs = []string{fmt.Sprintf(":%d", srv.config.Port)}
 	}
-	gnmipb.RegisterGNMIServer(srv.s, srv)
-	spb_jwt_gnoi.RegisterSonicJwtServiceServer(srv.s, srv)
-	if READ_WRITE_MODE {
-		gnoi_system_pb.RegisterSystemServer(srv.s, srv)
-		spb_gnoi.RegisterSonicServiceServer(srv.s, srv)
+	for _, addr := range addrs {
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			srv.closeListeners()
+			return nil, fmt.Errorf("failed to open listener %s: %v", addr, err)
+		}
+		if srv.lis == nil {
+			srv.lis = lis
+		} else {
+			srv.extraLis = append(srv.extraLis, lis)
+		}
+	}
+	if srv.config.UnixSocket != "" {
+		os.Remove(srv.config.UnixSocket)
+		lis, err := net.Listen("unix", srv.config.UnixSocket)
+		if err != nil {
+			srv.closeListeners()
+			return nil, fmt.Errorf("failed to open listener %s: %v", srv.config.UnixSocket, err)
+		}
+		srv.ulis = lis
+		uopts := append([]grpc.ServerOption{grpc.Creds(peerCredentials{})}, auditServerOptions(config)...)
+		srv.us = grpc.NewServer(uopts...)
+		srv.register(srv.us)
 	}
+	srv.register(srv.s)
 	log.V(1).Infof("Created Server on %s, read-only: %t", srv.Address(), !READ_WRITE_MODE)
 	return srv, nil
 }
 
+// register registers the services of the Server to a gRPC server.
+func (srv *Server) register(s *grpc.Server) {
+	reflection.Register(s)
+	gnmipb.RegisterGNMIServer(s, srv)
+	spb_jwt_gnoi.RegisterSonicJwtServiceServer(s, srv)
+	spb_admin.RegisterSonicAdminServiceServer(s, srv)
+	if READ_WRITE_MODE {
+		gnoi_system_pb.RegisterSystemServer(s, srv)
+		spb_gnoi.RegisterSonicServiceServer(s, srv)
+	}
+}
+
+func (srv *Server) closeListeners() {
+	for _, lis := range append([]net.Listener{srv.lis, srv.ulis}, srv.extraLis...) {
+		if lis != nil {
+			lis.Close()
+		}
+	}
+}
+
 // Serve will start the Server serving and block until closed.
 func (srv *Server) Serve() error {
 	s := srv.s
 	if s == nil {
 		return fmt.Errorf("Serve() failed: not initialized")
 	}
+	for _, lis := range srv.extraLis {
+		go srv.serve(srv.s, lis)
+	}
+	if srv.us != nil {
+		go srv.serve(srv.us, srv.ulis)
+	}
 	return srv.s.Serve(srv.lis)
 }
 
+func (srv *Server) serve(s *grpc.Server, lis net.Listener) {
+	log.V(1).Infof("Serving on %s", lis.Addr())
+	if err := s.Serve(lis); err != nil {
+		log.Errorf("Serving on %s failed: %v", lis.Addr(), err)
+	}
+}
+
+// Stop stops the server immediately, closing all its connections.
+func (srv *Server) Stop() error {
+	s := srv.s
+	if s == nil {
+		return fmt.Errorf("Stop() failed: not initialized")
+	}
+	srv.s.Stop()
+	if srv.us != nil {
+		srv.us.Stop()
+	}
+	log.V(1).Infof("Server stopped on %s", srv.Address())
+	return nil
+}
+
+// Shutdown stops the server gracefully. New RPCs are refused, the active
+// Subscribe sessions end with the Unavailable status and the other RPCs in
+// flight, like Set, are given until timeout to complete before the server
+// is stopped.
+func (srv *Server) Shutdown(timeout time.Duration) error {
+	s := srv.s
+	if s == nil {
+		return fmt.Errorf("Shutdown() failed: not initialized")
+	}
+
+	srv.cMu.Lock()
+	srv.stopping = true
+	clients := make([]*Client, 0, len(srv.clients))
+	for _, c := range srv.clients {
+		clients = append(clients, c)
+	}
+	srv.cMu.Unlock()
+
+	// done is closed when both the network and the unix socket servers
+	// are stopped.
+	var wg sync.WaitGroup
+	for _, gs := range []*grpc.Server{srv.s, srv.us} {
+		if gs != nil {
+			wg.Add(1)
+			go func(gs *grpc.Server) {
+				gs.GracefulStop()
+				wg.Done()
+			}(gs)
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for _, c := range clients {
+		log.V(1).Infof("Closing session %v of %v for shutdown", c.id, c)
+		c.terminate(status.Error(codes.Unavailable, "server shutting down"))
+	}
+
+	select {
+	case <-done:
+		log.V(1).Infof("Server shut down on %s", srv.Address())
+		return nil
+	case <-time.After(timeout):
+		srv.Stop()
+		<-done
+		return fmt.Errorf("RPCs still in flight after %v, server stopped", timeout)
+	}
+}
+
 // Address returns the port the Server is listening to.
 func (srv *Server) Address() string {
 	addr := srv.lis.Addr().String()
@@ -170,46 +324,36 @@ func (srv *Server) Port() int64 {
 
 func authenticate(UserAuth AuthTypes, ctx context.Context) (context.Context, error) {
 	var err error
-	success := false
 	rc, ctx := common_utils.GetContext(ctx)
+	if isPeerCredConn(ctx) {
+		// Clients of the unix socket are always identified by their peer
+		// credentials, whatever the auth modes.
+		rc.Auth.AuthEnabled = true
+		ctx, err = PeerCredAuthenAndAuthor(ctx)
+		countAuth("peercred", err == nil)
+		if err == nil {
+			rc.Auth.Mechanisms = []string{"peercred"}
+		}
+		return ctx, err
+	}
 	if !UserAuth.Any() {
 		//No Auth enabled
 		rc.Auth.AuthEnabled = false
 		return ctx, nil
 	}
 	rc.Auth.AuthEnabled = true
-	if UserAuth.Enabled("password") {
-		ctx, err = BasicAuthenAndAuthor(ctx)
-		if err == nil {
-			success = true
-		}
-	}
-	if !success && UserAuth.Enabled("jwt") {
-		_, ctx, err = JwtAuthenAndAuthor(ctx)
-		if err == nil {
-			success = true
-		}
-	}
-	if !success && UserAuth.Enabled("cert") {
-		ctx, err = ClientCertAuthenAndAuthor(ctx)
-		if err == nil {
-			success = true
-		}
+	alternatives, ruled := authAlternatives(ctx, UserAuth)
+	ctx, err = authenticateCombinations(UserAuth, ctx, alternatives)
+	if err != nil && ruled {
+		return ctx, status.Errorf(codes.Unauthenticated, "Unauthenticated, requires %v", authRequirement(alternatives))
 	}
-
-	//Allow for future authentication mechanisms here...
-
-	if !success {
-		return ctx, status.Error(codes.Unauthenticated, "Unauthenticated")
-	}
-
-	return ctx, nil
+	return ctx, err
 }
 
 // Subscribe implements the gNMI Subscribe RPC.
 func (s *Server) Subscribe(stream gnmipb.GNMI_SubscribeServer) error {
 	ctx := stream.Context()
-	ctx, err := authenticate(s.config.UserAuth, ctx)
+	ctx, err := authenticate(s.userAuth(), ctx)
 	if err != nil {
 		return err
 	}
@@ -223,17 +367,23 @@ func (s *Server) Subscribe(stream gnmipb.GNMI_SubscribeServer) error {
 		return grpc.Errorf(codes.InvalidArgument, "failed to get peer address")
 	}
 
-	/* TODO: authorize the user
-	msg, ok := credentials.AuthorizeUser(ctx)
-	if !ok {
-		log.Infof("denied a Set request: %v", msg)
-		return nil, status.Error(codes.PermissionDenied, msg)
-	}
-	*/
-
+	rc, _ := common_utils.GetContext(ctx)
 	c := NewClient(pr.Addr)
+	c.id = rc.ID
+	c.user = rc.Auth.User
+	c.start = time.Now()
+	if limit, policy := s.queueSettings(); limit > 0 {
+		c.SetQueueLimit(limit, policy)
+	}
+	c.authorize = func(prefix *gnmipb.Path, paths []*gnmipb.Path) error {
+		return s.authorize(ctx, AuthzSubscribe, prefix, paths)
+	}
 
 	s.cMu.Lock()
+	if s.stopping {
+		s.cMu.Unlock()
+		return status.Error(codes.Unavailable, "server shutting down")
+	}
 	if oc, ok := s.clients[c.String()]; ok {
 		log.V(2).Infof("Delete duplicate client %s", oc)
 		oc.Close()
@@ -243,9 +393,7 @@ func (s *Server) Subscribe(stream gnmipb.GNMI_SubscribeServer) error {
 	s.cMu.Unlock()
 
 	err = c.Run(stream)
-	s.cMu.Lock()
-	delete(s.clients, c.String())
-	s.cMu.Unlock()
+	s.closeSession(c)
 
 	log.Flush()
 	return err
@@ -267,17 +415,50 @@ func (s *Server) checkEncodingAndModel(encoding gnmipb.Encoding, models []*gnmip
 	return nil
 }
 
+// dataTypeTargets lists the SONiC DB targets holding the data of each
+// GetRequest data type. Data type ALL is valid for every target.
+var dataTypeTargets = map[gnmipb.GetRequest_DataType]map[string]bool{
+	gnmipb.GetRequest_CONFIG: {
+		"CONFIG_DB": true,
+	},
+	gnmipb.GetRequest_STATE: {
+		"STATE_DB":    true,
+		"APPL_DB":     true,
+		"COUNTERS_DB": true,
+		"OTHERS":      true,
+	},
+	gnmipb.GetRequest_OPERATIONAL: {
+		"STATE_DB":    true,
+		"APPL_DB":     true,
+		"COUNTERS_DB": true,
+		"OTHERS":      true,
+	},
+}
+
+// checkDataType verifies that the target holds data of the requested type.
+// Targets which are not SONiC DBs are served by translib, which filters the
+// data by type itself.
+func checkDataType(dataType gnmipb.GetRequest_DataType, target string) error {
+	if dataType == gnmipb.GetRequest_ALL {
+		return nil
+	}
+	dbName, ok, _, _ := sdc.IsTargetDb(target)
+	if !ok {
+		return nil
+	}
+	if !dataTypeTargets[dataType][dbName] {
+		return fmt.Errorf("data type %v is not supported for target %v", dataType, dbName)
+	}
+	return nil
+}
+
 // Get implements the Get RPC in gNMI spec.
 func (s *Server) Get(ctx context.Context, req *gnmipb.GetRequest) (*gnmipb.GetResponse, error) {
-	ctx, err := authenticate(s.config.UserAuth, ctx)
+	ctx, err := authenticate(s.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	if req.GetType() != gnmipb.GetRequest_ALL {
-		return nil, status.Errorf(codes.Unimplemented, "unsupported request type: %s", gnmipb.GetRequest_DataType_name[int32(req.GetType())])
-	}
-
 	if err = s.checkEncodingAndModel(req.GetEncoding(), req.GetUseModels()); err != nil {
 		return nil, status.Error(codes.Unimplemented, err.Error())
 	}
@@ -293,51 +474,95 @@ func (s *Server) Get(ctx context.Context, req *gnmipb.GetRequest) (*gnmipb.GetRe
 		}
 	}
 
+	if err = checkDataType(req.GetType(), target); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	paths := req.GetPath()
 	extensions := req.GetExtension()
 	target = prefix.GetTarget()
 	log.V(5).Infof("GetRequest paths: %v", paths)
 
+	if err = s.authorize(ctx, AuthzRead, prefix, paths); err != nil {
+		return nil, err
+	}
+
+	// Each path is fetched on its own so that a bad path does not hide the
+	// errors of the others.
+	var notifications []*gnmipb.Notification
+	var pathErrs []pathError
+	for _, path := range paths {
+		spbValues, err := getPath(ctx, prefix, path, extensions, req.GetType(), req.GetEncoding())
+		if err == nil {
+			var pathNotifications []*gnmipb.Notification
+			for _, spbValue := range spbValues {
+				var updates []*gnmipb.Update
+				if updates, err = sdc.ValToUpdates(spbValue, req.GetEncoding()); err != nil {
+					break
+				}
+				// Values read together, like the fields of a DB key
+				// with PROTO encoding, share a notification.
+				if n := len(pathNotifications); n > 0 && pathNotifications[n-1].GetPrefix() == spbValue.GetPrefix() &&
+					pathNotifications[n-1].GetTimestamp() == spbValue.GetTimestamp() {
+					pathNotifications[n-1].Update = append(pathNotifications[n-1].Update, updates...)
+					continue
+				}
+				pathNotifications = append(pathNotifications, &gnmipb.Notification{
+					Timestamp: spbValue.GetTimestamp(),
+					Prefix:    spbValue.GetPrefix(),
+					Update:    updates,
+				})
+			}
+			notifications = append(notifications, pathNotifications...)
+		}
+		if err != nil {
+			log.V(2).Infof("Get failed for path %v: %v", path, err)
+			pathErrs = append(pathErrs, newPathError(prefix, path, gnmipb.UpdateResult_INVALID, err, codes.NotFound))
+		}
+	}
+
+	if len(pathErrs) > 0 {
+		// gNMI fails the whole Get; the notifications of the valid paths
+		// are still returned as a GetResponse detail of the status.
+		var extra []proto.Message
+		if len(notifications) > 0 {
+			extra = append(extra, &gnmipb.GetResponse{Notification: notifications})
+		}
+		msg := pathErrs[0].err.Error()
+		if len(pathErrs) > 1 {
+			msg = fmt.Sprintf("%d of %d paths failed, first error: %v", len(pathErrs), len(paths), msg)
+		}
+		return nil, pathErrorStatus(msg, pathErrs, extra...)
+	}
+	return &gnmipb.GetResponse{Notification: notifications}, nil
+}
+
+// getPath returns the values of a single path of a GetRequest.
+func getPath(ctx context.Context, prefix, path *gnmipb.Path, extensions []*gnmi_extpb.Extension, dataType gnmipb.GetRequest_DataType, encoding gnmipb.Encoding) ([]*spb.Value, error) {
 	var dc sdc.Client
+	var err error
 
+	paths := []*gnmipb.Path{path}
+	target := prefix.GetTarget()
 	if target == "OTHERS" {
 		dc, err = sdc.NewNonDbClient(paths, prefix)
 	} else if _, ok, _, _ := sdc.IsTargetDb(target); ok {
-		dc, err = sdc.NewDbClient(paths, prefix)
+		dc, err = sdc.NewDbClient(paths, prefix, encoding)
 	} else {
 		/* If no prefix target is specified create new Transl Data Client . */
-		dc, err = sdc.NewTranslClient(prefix, paths, ctx, extensions)
-	}
-
-	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		dc, err = sdc.NewTranslGetClient(prefix, paths, ctx, extensions, dataType)
 	}
-	notifications := make([]*gnmipb.Notification, len(paths))
-	spbValues, err := dc.Get(nil)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
-	}
-
-	for index, spbValue := range spbValues {
-		update := &gnmipb.Update{
-			Path: spbValue.GetPath(),
-			Val:  spbValue.GetVal(),
-		}
-
-		notifications[index] = &gnmipb.Notification{
-			Timestamp: spbValue.GetTimestamp(),
-			Prefix:    prefix,
-			Update:    []*gnmipb.Update{update},
-		}
+		return nil, err
 	}
-	return &gnmipb.GetResponse{Notification: notifications}, nil
+	return dc.Get(nil)
 }
 
 func (s *Server) Set(ctx context.Context, req *gnmipb.SetRequest) (*gnmipb.SetResponse, error) {
 	if !READ_WRITE_MODE {
 		return nil, grpc.Errorf(codes.Unimplemented, "Telemetry is in read-only mode")
 	}
-	ctx, err := authenticate(s.config.UserAuth, ctx)
+	ctx, err := authenticate(s.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -346,8 +571,34 @@ func (s *Server) Set(ctx context.Context, req *gnmipb.SetRequest) (*gnmipb.SetRe
 	/* Fetch the prefix. */
 	prefix := req.GetPrefix()
 	extensions := req.GetExtension()
-	/* Create Transl client. */
-	dc, _ := sdc.NewTranslClient(prefix, nil, ctx, extensions)
+
+	paths := append([]*gnmipb.Path{}, req.GetDelete()...)
+	for _, u := range req.GetReplace() {
+		paths = append(paths, u.GetPath())
+	}
+	for _, u := range req.GetUpdate() {
+		paths = append(paths, u.GetPath())
+	}
+	if err = s.authorize(ctx, AuthzWrite, prefix, paths); err != nil {
+		return nil, err
+	}
+
+	var dc sdc.Client
+	/* Errors of Transl client carry their own code. */
+	errCode := codes.Unknown
+	if _, ok, _, _ := sdc.IsTargetDb(prefix.GetTarget()); ok {
+		/* Set of DB client fails on invalid targets, paths or values,
+		 * redis failures carry the Internal code. */
+		errCode = codes.InvalidArgument
+		/* Create DB client for SONiC DB targets. */
+		dc, err = sdc.NewDbClient(nil, prefix, gnmipb.Encoding_JSON_IETF)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	} else {
+		/* Create Transl client. */
+		dc, _ = sdc.NewTranslClient(prefix, nil, ctx, extensions)
+	}
 
 	/* DELETE */
 	for _, path := range req.GetDelete() {
@@ -387,16 +638,19 @@ func (s *Server) Set(ctx context.Context, req *gnmipb.SetRequest) (*gnmipb.SetRe
 		results = append(results, &res)
 	}
 	err = dc.Set(req.GetDelete(), req.GetReplace(), req.GetUpdate())
+	if err != nil {
+		return nil, setErrorStatus(req, err, errCode)
+	}
 
 	return &gnmipb.SetResponse{
 		Prefix:   req.GetPrefix(),
 		Response: results,
-	}, err
+	}, nil
 
 }
 
 func (s *Server) Capabilities(ctx context.Context, req *gnmipb.CapabilityRequest) (*gnmipb.CapabilityResponse, error) {
-	ctx, err := authenticate(s.config.UserAuth, ctx)
+	ctx, err := authenticate(s.userAuth(), ctx)
 	if err != nil {
 		return nil, err
 	}
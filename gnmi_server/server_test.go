@@ -3,20 +3,40 @@ package gnmi
 // server_test covers gNMI get, subscribe (stream and poll) test
 // Prerequisite: redis-server should be running.
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"flag"
 	"fmt"
 	"strings"
 
+	"github.com/Azure/sonic-telemetry/audit"
+	"github.com/Azure/sonic-telemetry/common_utils"
+	"github.com/Azure/sonic-telemetry/metrics"
 	testcert "github.com/Azure/sonic-telemetry/testdata/tls"
+	"github.com/Workiva/go-datastructures/queue"
+	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/go-redis/redis"
 	"github.com/golang/protobuf/proto"
 
 	"io/ioutil"
+	"math/big"
+	"net"
+	"net/url"
 	"os"
 	"os/exec"
+	"os/user"
+	"path/filepath"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,15 +47,21 @@ import (
 	"github.com/openconfig/gnmi/value"
 	"github.com/openconfig/ygot/ygot"
 
+	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
 	// Register supported client types.
 	spb "github.com/Azure/sonic-telemetry/proto"
 	sgpb "github.com/Azure/sonic-telemetry/proto/gnoi"
+	spb_admin "github.com/Azure/sonic-telemetry/proto/gnoi/admin"
+	sgpb_jwt "github.com/Azure/sonic-telemetry/proto/gnoi/jwt"
 	sdc "github.com/Azure/sonic-telemetry/sonic_data_client"
 	sdcfg "github.com/Azure/sonic-telemetry/sonic_db_config"
 	"github.com/Azure/sonic-telemetry/test_utils"
@@ -104,6 +130,24 @@ func createServer(t *testing.T, port int64) *Server {
 	return s
 }
 
+func createServerWithConfig(t *testing.T, cfg *Config) *Server {
+	certificate, err := testcert.NewCert()
+	if err != nil {
+		t.Errorf("could not load server key pair: %s", err)
+	}
+	tlsCfg := &tls.Config{
+		ClientAuth:   tls.RequestClientCert,
+		Certificates: []tls.Certificate{certificate},
+	}
+
+	opts := []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))}
+	s, err := NewServer(cfg, opts)
+	if err != nil {
+		t.Errorf("Failed to create gNMI server: %v", err)
+	}
+	return s
+}
+
 // runTestGet requests a path from the server by Get grpc call, and compares if
 // the return code and response value are expected.
 func runTestGet(t *testing.T, ctx context.Context, gClient pb.GNMIClient, pathTarget string,
@@ -437,9 +481,11 @@ func prepareDbTranslib(t *testing.T) {
 
 // subscriptionQuery represent the input to create an gnmi.Subscription instance.
 type subscriptionQuery struct {
-	Query          []string
-	SubMode        pb.SubscriptionMode
-	SampleInterval uint64
+	Query             []string
+	SubMode           pb.SubscriptionMode
+	SampleInterval    uint64
+	HeartbeatInterval uint64
+	SuppressRedundant bool
 }
 
 func pathToString(q client.Path) string {
@@ -468,14 +514,16 @@ func createQuery(subListMode pb.SubscriptionList_Mode, target string, queries []
 	for _, qq := range queries {
 		pp, err := ygot.StringToPath(pathToString(qq.Query), ygot.StructuredPath, ygot.StringSlicePath)
 		if err != nil {
-			return nil, fmt.Errorf("invalid query path %q: %v", qq, err)
+			return nil, fmt.Errorf("invalid query path %q: %v", qq.Query, err)
 		}
 		s.Subscribe.Subscription = append(
 			s.Subscribe.Subscription,
 			&pb.Subscription{
-				Path:           pp,
-				Mode:           qq.SubMode,
-				SampleInterval: qq.SampleInterval,
+				Path:              pp,
+				Mode:              qq.SubMode,
+				SampleInterval:    qq.SampleInterval,
+				HeartbeatInterval: qq.HeartbeatInterval,
+				SuppressRedundant: qq.SuppressRedundant,
 			})
 	}
 
@@ -524,6 +572,23 @@ func createCountersDbQuerySampleMode(t *testing.T, interval time.Duration, updat
 		updateOnly)
 }
 
+// createCountersDbQueryHeartbeat creates a query with a heartbeat interval,
+// suppressing redundant SAMPLE data.
+func createCountersDbQueryHeartbeat(t *testing.T, mode pb.SubscriptionMode, heartbeat time.Duration, paths ...string) client.Query {
+	return createQueryOrFail(t,
+		pb.SubscriptionList_STREAM,
+		"COUNTERS_DB",
+		[]subscriptionQuery{
+			{
+				Query:             paths,
+				SubMode:           mode,
+				HeartbeatInterval: uint64(heartbeat.Nanoseconds()),
+				SuppressRedundant: mode == pb.SubscriptionMode_SAMPLE,
+			},
+		},
+		false)
+}
+
 // createCountersTableSetUpdate creates a HSET request on the COUNTERS table.
 func createCountersTableSetUpdate(tableKey string, fieldName string, fieldValue string) tablePathValue {
 	return tablePathValue{
@@ -549,6 +614,15 @@ func createCountersTableDeleteUpdate(tableKey string, fieldName string) tablePat
 	}
 }
 
+// createCountersTableReconnectUpdate creates a HSET request on the COUNTERS
+// table, done right after closing the pubsub connections of redis so that its
+// keyspace notification is lost.
+func createCountersTableReconnectUpdate(tableKey string, fieldName string, fieldValue string) tablePathValue {
+	update := createCountersTableSetUpdate(tableKey, fieldName, fieldValue)
+	update.op = "reconnect"
+	return update
+}
+
 // createIntervalTickerUpdate creates a request for triggering the interval clock.
 func createIntervalTickerUpdate() tablePathValue {
 	return tablePathValue{
@@ -696,6 +770,219 @@ func TestGnmiSet(t *testing.T) {
 	s.s.Stop()
 }
 
+func TestGnmiSetDb(t *testing.T) {
+	if !READ_WRITE_MODE {
+		t.Skip("skipping test in read-only mode.")
+	}
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	defer s.s.Stop()
+
+	rclient := getConfigDbClient(t, sdcfg.GetDbDefaultNamespace())
+	defer rclient.Close()
+	rclient.FlushDB()
+	rclient.HMSet("PORT|Ethernet0", map[string]interface{}{"admin_status": "down", "mtu": "9100"})
+	rclient.HMSet("PORT|Ethernet4", map[string]interface{}{"admin_status": "down", "mtu": "9100"})
+	rclient.HMSet("VLAN|Vlan200", map[string]interface{}{"vlanid": "200"})
+	rclient.HMSet("VLAN_MEMBER|Vlan200|Ethernet0", map[string]interface{}{"tagging_mode": "tagged"})
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+
+	targetAddr := "127.0.0.1:8081"
+	conn, err := grpc.Dial(targetAddr, opts...)
+	if err != nil {
+		t.Fatalf("Dialing to %q failed: %v", targetAddr, err)
+	}
+	defer conn.Close()
+
+	gClient := pb.NewGNMIClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	path := func(elems ...string) *pb.Path {
+		p := &pb.Path{}
+		for _, e := range elems {
+			p.Elem = append(p.Elem, &pb.PathElem{Name: e})
+		}
+		return p
+	}
+	strVal := func(s string) *pb.TypedValue {
+		return &pb.TypedValue{Value: &pb.TypedValue_StringVal{StringVal: s}}
+	}
+	jsonVal := func(s string) *pb.TypedValue {
+		return &pb.TypedValue{Value: &pb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(s)}}
+	}
+
+	tds := []struct {
+		desc    string
+		target  string
+		req     *pb.SetRequest
+		wantErr bool
+		// wantFailed lists the operation and path of each failed operation.
+		wantFailed []string
+		// wantDb is the expected content of each key after the Set, nil if the key must not exist.
+		wantDb map[string]map[string]string
+	}{
+		{
+			desc:   "update field",
+			target: "CONFIG_DB",
+			req: &pb.SetRequest{
+				Update: []*pb.Update{{Path: path("PORT", "Ethernet0", "admin_status"), Val: strVal("up")}},
+			},
+			wantDb: map[string]map[string]string{
+				"PORT|Ethernet0": {"admin_status": "up", "mtu": "9100"},
+			},
+		},
+		{
+			desc:   "update key",
+			target: "CONFIG_DB",
+			req: &pb.SetRequest{
+				Update: []*pb.Update{{Path: path("PORT", "Ethernet0"), Val: jsonVal(`{"mtu": "9000", "speed": 100000}`)}},
+			},
+			wantDb: map[string]map[string]string{
+				"PORT|Ethernet0": {"admin_status": "up", "mtu": "9000", "speed": "100000"},
+			},
+		},
+		{
+			desc:   "replace key",
+			target: "CONFIG_DB",
+			req: &pb.SetRequest{
+				Replace: []*pb.Update{{Path: path("PORT", "Ethernet4"), Val: jsonVal(`{"alias": "etp2"}`)}},
+			},
+			wantDb: map[string]map[string]string{
+				"PORT|Ethernet4": {"alias": "etp2"},
+			},
+		},
+		{
+			desc:   "delete field and key",
+			target: "CONFIG_DB",
+			req: &pb.SetRequest{
+				Delete: []*pb.Path{path("PORT", "Ethernet0", "speed"), path("PORT", "Ethernet4")},
+			},
+			wantDb: map[string]map[string]string{
+				"PORT|Ethernet0": {"admin_status": "up", "mtu": "9000"},
+				"PORT|Ethernet4": nil,
+			},
+		},
+		{
+			desc:   "delete missing key",
+			target: "CONFIG_DB",
+			req: &pb.SetRequest{
+				Delete: []*pb.Path{path("PORT", "Ethernet999"), path("PORT", "Ethernet999", "mtu")},
+			},
+			wantDb: map[string]map[string]string{
+				"PORT|Ethernet0":   {"admin_status": "up", "mtu": "9000"},
+				"PORT|Ethernet999": nil,
+			},
+		},
+		{
+			desc:   "replace table",
+			target: "CONFIG_DB",
+			req: &pb.SetRequest{
+				Replace: []*pb.Update{{Path: path("VLAN"), Val: jsonVal(`{"Vlan100": {"vlanid": "100", "members@": ["Ethernet0", "Ethernet4"]}}`)}},
+			},
+			wantDb: map[string]map[string]string{
+				"VLAN|Vlan100":                  {"vlanid": "100", "members@": "Ethernet0,Ethernet4"},
+				"VLAN|Vlan200":                  nil,
+				"VLAN_MEMBER|Vlan200|Ethernet0": {"tagging_mode": "tagged"},
+			},
+		},
+		{
+			desc:   "delete table",
+			target: "CONFIG_DB",
+			req: &pb.SetRequest{
+				Delete: []*pb.Path{path("VLAN")},
+			},
+			wantDb: map[string]map[string]string{
+				"VLAN|Vlan100":                  nil,
+				"VLAN_MEMBER|Vlan200|Ethernet0": {"tagging_mode": "tagged"},
+			},
+		},
+		{
+			desc:   "invalid value aborts whole request",
+			target: "CONFIG_DB",
+			req: &pb.SetRequest{
+				Update: []*pb.Update{
+					{Path: path("PORT", "Ethernet0", "admin_status"), Val: strVal("down")},
+					{Path: path("PORT"), Val: strVal("up")},
+				},
+			},
+			wantErr: true,
+			wantDb: map[string]map[string]string{
+				"PORT|Ethernet0": {"admin_status": "up", "mtu": "9000"},
+			},
+		},
+		{
+			desc:   "every invalid operation is reported",
+			target: "CONFIG_DB",
+			req: &pb.SetRequest{
+				Delete: []*pb.Path{{}},
+				Update: []*pb.Update{
+					{Path: path("PORT", "Ethernet0", "admin_status"), Val: strVal("down")},
+					{Path: path("PORT", "Ethernet0"), Val: jsonVal(`{"mtu": {"value": 9000}}`)},
+				},
+			},
+			wantErr:    true,
+			wantFailed: []string{"DELETE /", "UPDATE /PORT/Ethernet0"},
+			wantDb: map[string]map[string]string{
+				"PORT|Ethernet0": {"admin_status": "up", "mtu": "9000"},
+			},
+		},
+		{
+			desc:   "read-only target",
+			target: "COUNTERS_DB",
+			req: &pb.SetRequest{
+				Update: []*pb.Update{{Path: path("COUNTERS", "Ethernet0", "x"), Val: strVal("1")}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, td := range tds {
+		t.Run(td.desc, func(t *testing.T) {
+			td.req.Prefix = &pb.Path{Target: td.target}
+			resp, err := gClient.Set(ctx, td.req)
+			if td.wantErr {
+				if err == nil {
+					t.Fatalf("Set succeeded, want error")
+				}
+				if td.wantFailed != nil {
+					var failed []string
+					for _, d := range status.Convert(err).Details() {
+						if info, ok := d.(*errdetails.ErrorInfo); ok {
+							failed = append(failed, info.GetMetadata()["operation"]+" "+info.GetMetadata()["path"])
+						}
+					}
+					if !reflect.DeepEqual(failed, td.wantFailed) {
+						t.Errorf("got failed operations %v, want %v", failed, td.wantFailed)
+					}
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Set failed: %v", err)
+				}
+				wantResults := len(td.req.GetDelete()) + len(td.req.GetReplace()) + len(td.req.GetUpdate())
+				if len(resp.GetResponse()) != wantResults {
+					t.Fatalf("got %d results, want %d", len(resp.GetResponse()), wantResults)
+				}
+			}
+			for key, want := range td.wantDb {
+				got, err := rclient.HGetAll(key).Result()
+				if err != nil {
+					t.Fatalf("HGetAll %v failed: %v", key, err)
+				}
+				if want == nil {
+					want = map[string]string{}
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("key %v: got %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
 func runGnmiTestGet(t *testing.T, namespace string) {
 	//t.Log("Start gNMI client")
 	tlsConfig := &tls.Config{InsecureSkipVerify: true}
@@ -999,39 +1286,150 @@ func TestGnmiGet(t *testing.T) {
 
 	s.s.Stop()
 }
-func TestGnmiGetMultiNs(t *testing.T) {
-	sdcfg.Init()
-	err := test_utils.SetupMultiNamespace()
+func TestGnmiGetDataType(t *testing.T) {
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	defer s.s.Stop()
+
+	prepareDb(t, sdcfg.GetDbDefaultNamespace())
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+
+	targetAddr := "127.0.0.1:8081"
+	conn, err := grpc.Dial(targetAddr, opts...)
 	if err != nil {
-		t.Fatalf("error Setting up MultiNamespace files with err %T", err)
+		t.Fatalf("Dialing to %q failed: %v", targetAddr, err)
 	}
+	defer conn.Close()
 
-	/* https://www.gopherguides.com/articles/test-cleanup-in-go-1-14*/
-	t.Cleanup(func() {
-		if err := test_utils.CleanUpMultiNamespace(); err != nil {
-			t.Fatalf("error Cleaning up MultiNamespace files with err %T", err)
+	gClient := pb.NewGNMIClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-		}
-	})
+	tds := []struct {
+		desc        string
+		target      string
+		dataType    pb.GetRequest_DataType
+		wantRetCode codes.Code
+	}{
+		{
+			desc:        "state data of COUNTERS_DB",
+			target:      "COUNTERS_DB",
+			dataType:    pb.GetRequest_STATE,
+			wantRetCode: codes.OK,
+		}, {
+			desc:        "operational data of COUNTERS_DB",
+			target:      "COUNTERS_DB",
+			dataType:    pb.GetRequest_OPERATIONAL,
+			wantRetCode: codes.OK,
+		}, {
+			desc:        "config data of COUNTERS_DB",
+			target:      "COUNTERS_DB",
+			dataType:    pb.GetRequest_CONFIG,
+			wantRetCode: codes.InvalidArgument,
+		}, {
+			desc:        "state data of CONFIG_DB",
+			target:      "CONFIG_DB",
+			dataType:    pb.GetRequest_STATE,
+			wantRetCode: codes.InvalidArgument,
+		},
+	}
 
-	//t.Log("Start server")
+	for _, td := range tds {
+		t.Run(td.desc, func(t *testing.T) {
+			req := &pb.GetRequest{
+				Prefix:   &pb.Path{Target: td.target},
+				Path:     []*pb.Path{{Elem: []*pb.PathElem{{Name: "COUNTERS_PORT_NAME_MAP"}}}},
+				Type:     td.dataType,
+				Encoding: pb.Encoding_JSON_IETF,
+			}
+			_, err := gClient.Get(ctx, req)
+			if got := status.Code(err); got != td.wantRetCode {
+				t.Fatalf("got return code %v, want %v: %v", got, td.wantRetCode, err)
+			}
+		})
+	}
+}
+
+func TestGnmiGetPartialErrors(t *testing.T) {
 	s := createServer(t, 8081)
 	go runServer(t, s)
+	defer s.s.Stop()
 
-	prepareDb(t, test_utils.GetMultiNsNamespace())
+	prepareDb(t, sdcfg.GetDbDefaultNamespace())
 
-	runGnmiTestGet(t, test_utils.GetMultiNsNamespace())
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
 
-	s.s.Stop()
+	targetAddr := "127.0.0.1:8081"
+	conn, err := grpc.Dial(targetAddr, opts...)
+	if err != nil {
+		t.Fatalf("Dialing to %q failed: %v", targetAddr, err)
+	}
+	defer conn.Close()
+
+	gClient := pb.NewGNMIClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	path := func(elems ...string) *pb.Path {
+		p := &pb.Path{}
+		for _, e := range elems {
+			p.Elem = append(p.Elem, &pb.PathElem{Name: e})
+		}
+		return p
+	}
+	req := &pb.GetRequest{
+		Prefix: &pb.Path{Target: "COUNTERS_DB"},
+		Path: []*pb.Path{
+			path("COUNTERS_PORT_NAME_MAP"),
+			path("NO_SUCH_TABLE_1"),
+			path("COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"),
+			path("NO_SUCH_TABLE_2"),
+		},
+		Encoding: pb.Encoding_JSON_IETF,
+	}
+	_, err = gClient.Get(ctx, req)
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatal("got a non-grpc error from grpc call")
+	}
+	if st.Code() != codes.NotFound {
+		t.Fatalf("got return code %v, want %v", st.Code(), codes.NotFound)
+	}
+
+	var failed []string
+	var notifications int
+	for _, d := range st.Details() {
+		switch detail := d.(type) {
+		case *errdetails.ErrorInfo:
+			if detail.GetReason() != "NOT_FOUND" {
+				t.Errorf("got reason %v, want NOT_FOUND", detail.GetReason())
+			}
+			failed = append(failed, detail.GetMetadata()["path"])
+		case *pb.GetResponse:
+			notifications = len(detail.GetNotification())
+		default:
+			t.Errorf("unexpected detail %T", d)
+		}
+	}
+	wantFailed := []string{"/NO_SUCH_TABLE_1", "/NO_SUCH_TABLE_2"}
+	if !reflect.DeepEqual(failed, wantFailed) {
+		t.Errorf("got failed paths %v, want %v", failed, wantFailed)
+	}
+	if notifications != 2 {
+		t.Errorf("got %d notifications for the valid paths, want 2", notifications)
+	}
 }
-func TestGnmiGetTranslib(t *testing.T) {
-	//t.Log("Start server")
+
+func TestGnmiGetProtoEncoding(t *testing.T) {
 	s := createServer(t, 8081)
 	go runServer(t, s)
+	defer s.s.Stop()
 
-	prepareDbTranslib(t)
+	prepareDb(t, sdcfg.GetDbDefaultNamespace())
 
-	//t.Log("Start gNMI client")
 	tlsConfig := &tls.Config{InsecureSkipVerify: true}
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
 
@@ -1046,44 +1444,190 @@ func TestGnmiGetTranslib(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var emptyRespVal interface{}
 	tds := []struct {
 		desc        string
-		pathTarget  string
-		textPbPath  string
-		wantRetCode codes.Code
-		wantRespVal interface{}
-		valTest     bool
+		path        []string
+		wantUpdates int
+		wantVal     map[string]*pb.TypedValue
 	}{
+		{
+			desc:        "field of a port",
+			path:        []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"},
+			wantUpdates: 1,
+			wantVal: map[string]*pb.TypedValue{
+				"/COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS": {Value: &pb.TypedValue_UintVal{UintVal: 2}},
+			},
+		}, {
+			desc:        "all fields of a port",
+			path:        []string{"COUNTERS", "Ethernet68"},
+			wantUpdates: 106,
+			wantVal: map[string]*pb.TypedValue{
+				"/COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS": {Value: &pb.TypedValue_UintVal{UintVal: 2}},
+			},
+		}, {
+			desc:        "field of all ports",
+			path:        []string{"COUNTERS", "Ethernet*", "SAI_PORT_STAT_PFC_7_RX_PKTS"},
+			wantUpdates: 2,
+			wantVal: map[string]*pb.TypedValue{
+				"/COUNTERS/Ethernet1/SAI_PORT_STAT_PFC_7_RX_PKTS":  {Value: &pb.TypedValue_UintVal{UintVal: 1}},
+				"/COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS": {Value: &pb.TypedValue_UintVal{UintVal: 2}},
+			},
+		},
+	}
 
-		//These tests only work on the real switch platform, since they rely on files in the /proc and another running service
-		// 	{
-		// 	desc:       "Get OC Platform",
-		// 	pathTarget: "OC_YANG",
-		// 	textPbPath: `
-		//                        elem: <name: "openconfig-platform:components" >
-		//                `,
-		// 	wantRetCode: codes.OK,
-		// 	wantRespVal: emptyRespVal,
-		// 	valTest:     false,
-		// },
-		// 	{
-		// 		desc:       "Get OC System State",
-		// 		pathTarget: "OC_YANG",
-		// 		textPbPath: `
-		//                        elem: <name: "openconfig-system:system" > elem: <name: "state" >
-		//                `,
-		// 		wantRetCode: codes.OK,
-		// 		wantRespVal: emptyRespVal,
-		// 		valTest:     false,
-		// 	},
-		// 	{
-		// 		desc:       "Get OC System CPU",
-		// 		pathTarget: "OC_YANG",
-		// 		textPbPath: `
-		//                        elem: <name: "openconfig-system:system" > elem: <name: "cpus" >
-		//                `,
-		// 		wantRetCode: codes.OK,
+	for _, td := range tds {
+		t.Run(td.desc, func(t *testing.T) {
+			path := &pb.Path{}
+			for _, e := range td.path {
+				path.Elem = append(path.Elem, &pb.PathElem{Name: e})
+			}
+			req := &pb.GetRequest{
+				Prefix:   &pb.Path{Target: "COUNTERS_DB"},
+				Path:     []*pb.Path{path},
+				Encoding: pb.Encoding_PROTO,
+			}
+			resp, err := gClient.Get(ctx, req)
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+
+			got := make(map[string]*pb.TypedValue)
+			for _, n := range resp.GetNotification() {
+				for _, u := range n.GetUpdate() {
+					var p string
+					for _, e := range append(n.GetPrefix().GetElem(), u.GetPath().GetElem()...) {
+						p += "/" + e.GetName()
+					}
+					got[p] = u.GetVal()
+				}
+			}
+			if len(got) != td.wantUpdates {
+				t.Errorf("got %d updates, want %d", len(got), td.wantUpdates)
+			}
+			for p, want := range td.wantVal {
+				if !proto.Equal(got[p], want) {
+					t.Errorf("got value %v for %s, want %v", got[p], p, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValToUpdatesProto(t *testing.T) {
+	val := &spb.Value{
+		Path: &pb.Path{Elem: []*pb.PathElem{{Name: "config"}}},
+		Val: &pb.TypedValue{Value: &pb.TypedValue_JsonIetfVal{
+			JsonIetfVal: []byte(`{"openconfig-interfaces:config": {"description": "100", "enabled": true, "mtu": 9100}}`),
+		}},
+	}
+	updates, err := sdc.ValToUpdates(val, pb.Encoding_PROTO)
+	if err != nil {
+		t.Fatalf("ValToUpdates failed: %v", err)
+	}
+	want := map[string]*pb.TypedValue{
+		"/config/description": {Value: &pb.TypedValue_StringVal{StringVal: "100"}},
+		"/config/enabled":     {Value: &pb.TypedValue_BoolVal{BoolVal: true}},
+		"/config/mtu":         {Value: &pb.TypedValue_UintVal{UintVal: 9100}},
+	}
+	if len(updates) != len(want) {
+		t.Fatalf("got %d updates, want %d", len(updates), len(want))
+	}
+	for _, u := range updates {
+		var p string
+		for _, e := range u.GetPath().GetElem() {
+			p += "/" + e.GetName()
+		}
+		if !proto.Equal(u.GetVal(), want[p]) {
+			t.Errorf("got value %v for %s, want %v", u.GetVal(), p, want[p])
+		}
+	}
+}
+
+func TestGnmiGetMultiNs(t *testing.T) {
+	sdcfg.Init()
+	err := test_utils.SetupMultiNamespace()
+	if err != nil {
+		t.Fatalf("error Setting up MultiNamespace files with err %T", err)
+	}
+
+	/* https://www.gopherguides.com/articles/test-cleanup-in-go-1-14*/
+	t.Cleanup(func() {
+		if err := test_utils.CleanUpMultiNamespace(); err != nil {
+			t.Fatalf("error Cleaning up MultiNamespace files with err %T", err)
+
+		}
+	})
+
+	//t.Log("Start server")
+	s := createServer(t, 8081)
+	go runServer(t, s)
+
+	prepareDb(t, test_utils.GetMultiNsNamespace())
+
+	runGnmiTestGet(t, test_utils.GetMultiNsNamespace())
+
+	s.s.Stop()
+}
+func TestGnmiGetTranslib(t *testing.T) {
+	//t.Log("Start server")
+	s := createServer(t, 8081)
+	go runServer(t, s)
+
+	prepareDbTranslib(t)
+
+	//t.Log("Start gNMI client")
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+
+	targetAddr := "127.0.0.1:8081"
+	conn, err := grpc.Dial(targetAddr, opts...)
+	if err != nil {
+		t.Fatalf("Dialing to %q failed: %v", targetAddr, err)
+	}
+	defer conn.Close()
+
+	gClient := pb.NewGNMIClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var emptyRespVal interface{}
+	tds := []struct {
+		desc        string
+		pathTarget  string
+		textPbPath  string
+		wantRetCode codes.Code
+		wantRespVal interface{}
+		valTest     bool
+	}{
+
+		//These tests only work on the real switch platform, since they rely on files in the /proc and another running service
+		// 	{
+		// 	desc:       "Get OC Platform",
+		// 	pathTarget: "OC_YANG",
+		// 	textPbPath: `
+		//                        elem: <name: "openconfig-platform:components" >
+		//                `,
+		// 	wantRetCode: codes.OK,
+		// 	wantRespVal: emptyRespVal,
+		// 	valTest:     false,
+		// },
+		// 	{
+		// 		desc:       "Get OC System State",
+		// 		pathTarget: "OC_YANG",
+		// 		textPbPath: `
+		//                        elem: <name: "openconfig-system:system" > elem: <name: "state" >
+		//                `,
+		// 		wantRetCode: codes.OK,
+		// 		wantRespVal: emptyRespVal,
+		// 		valTest:     false,
+		// 	},
+		// 	{
+		// 		desc:       "Get OC System CPU",
+		// 		pathTarget: "OC_YANG",
+		// 		textPbPath: `
+		//                        elem: <name: "openconfig-system:system" > elem: <name: "cpus" >
+		//                `,
+		// 		wantRetCode: codes.OK,
 		// 		wantRespVal: emptyRespVal,
 		// 		valTest:     false,
 		// 	},
@@ -1962,6 +2506,37 @@ func runTestSubscribe(t *testing.T, namespace string) {
 				client.Sync{},
 			},
 		},
+		{
+			desc: "once query for table COUNTERS_PORT_NAME_MAP",
+			q: client.Query{
+				Target:  "COUNTERS_DB",
+				Type:    client.Once,
+				Queries: []client.Path{{"COUNTERS_PORT_NAME_MAP"}},
+				TLS:     &tls.Config{InsecureSkipVerify: true},
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS_PORT_NAME_MAP"}, TS: time.Unix(0, 200), Val: countersPortNameMapJson},
+				client.Sync{},
+			},
+		},
+		{
+			desc: "once query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS",
+			q: client.Query{
+				Target:  "COUNTERS_DB",
+				Type:    client.Once,
+				Queries: []client.Path{{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}},
+				TLS:     &tls.Config{InsecureSkipVerify: true},
+			},
+			updates: []tablePathValue{
+				createCountersTableSetUpdate("oid:0x1000000000039", "SAI_PORT_STAT_PFC_7_RX_PKTS", "3"), // stream already closed
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+			},
+		},
 		{
 			desc:       "use invalid sample interval",
 			q:          createCountersDbQuerySampleMode(t, 10*time.Millisecond, false, "COUNTERS", "Ethernet1"),
@@ -2079,6 +2654,148 @@ func runTestSubscribe(t *testing.T, namespace string) {
 				client.Update{Path: []string{"COUNTERS", "Ethernet*"}, TS: time.Unix(0, 200), Val: map[string]interface{}{}}, //empty update
 			},
 		},
+		{
+			desc: "target defined stream query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS sampled",
+			q: createQueryOrFail(t, pb.SubscriptionList_STREAM, "COUNTERS_DB", []subscriptionQuery{
+				{
+					Query:   []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"},
+					SubMode: pb.SubscriptionMode_TARGET_DEFINED,
+				},
+			}, false),
+			generateIntervals: true,
+			updates: []tablePathValue{
+				createCountersTableSetUpdate("oid:0x1000000000039", "SAI_PORT_STAT_PFC_7_RX_PKTS", "3"), // be changed to 3 from 2
+				createIntervalTickerUpdate(), // no value change but imitate interval ticker
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "3"},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "3"},
+			},
+		},
+		{
+			desc: "stream query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS with updates of other fields and delete",
+			q:    createCountersDbQueryOnChangeMode(t, "COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"),
+			updates: []tablePathValue{
+				createCountersTableSetUpdate("oid:0x1000000000039", "test_field", "test_value"), // same key, no value change
+				createCountersTableDeleteUpdate("oid:0x1000000000039", "SAI_PORT_STAT_PFC_7_RX_PKTS"),
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: ""},
+			},
+		},
+		{
+			desc: "stream query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS with update while reconnecting",
+			q:    createCountersDbQueryOnChangeMode(t, "COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"),
+			updates: []tablePathValue{
+				createCountersTableReconnectUpdate("oid:0x1000000000039", "SAI_PORT_STAT_PFC_7_RX_PKTS", "3"), // be changed to 3 from 2
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "3"},
+			},
+		},
+		{
+			desc: "stream query for COUNTERS/Ethernet*/SAI_PORT_STAT_PFC_7_RX_PKTS with update while reconnecting",
+			q:    createCountersDbQueryOnChangeMode(t, "COUNTERS", "Ethernet*", "SAI_PORT_STAT_PFC_7_RX_PKTS"),
+			updates: []tablePathValue{
+				createCountersTableReconnectUpdate("oid:0x1000000000039", "SAI_PORT_STAT_PFC_7_RX_PKTS", "4"), // being changed to 4 from 2
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet*", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: countersEthernetWildcardPfcJson},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet*", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: singlePortPfcJsonUpdate},
+			},
+		},
+		{
+			desc:              "(heartbeat) stream query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS with no change",
+			q:                 createCountersDbQueryHeartbeat(t, pb.SubscriptionMode_ON_CHANGE, 2*sdc.MinSampleInterval, "COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"),
+			generateIntervals: true,
+			updates: []tablePathValue{
+				createIntervalTickerUpdate(), // heartbeat
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+			},
+		},
+		{
+			desc:       "use invalid heartbeat interval",
+			q:          createCountersDbQueryHeartbeat(t, pb.SubscriptionMode_ON_CHANGE, 10*time.Millisecond, "COUNTERS", "Ethernet1"),
+			updates:    []tablePathValue{},
+			wantSubErr: fmt.Errorf("rpc error: code = InvalidArgument desc = invalid heartbeat interval: 10ms. It cannot be less than %v", sdc.MinSampleInterval),
+			wantNoti:   []client.Notification{},
+		},
+		{
+			desc:              "(suppress redundant) sample stream query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS with 1 update",
+			q:                 createCountersDbQueryHeartbeat(t, pb.SubscriptionMode_SAMPLE, 0, "COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"),
+			generateIntervals: true,
+			updates: []tablePathValue{
+				createIntervalTickerUpdate(), // no value change, suppressed
+				createCountersTableSetUpdate("oid:0x1000000000039", "SAI_PORT_STAT_PFC_7_RX_PKTS", "3"),
+				createIntervalTickerUpdate(), // no value change, suppressed
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "3"},
+			},
+		},
+		{
+			desc:              "(suppress redundant) sample stream query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS with heartbeat every 2 intervals",
+			q:                 createCountersDbQueryHeartbeat(t, pb.SubscriptionMode_SAMPLE, 2*sdc.MinSampleInterval, "COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"),
+			generateIntervals: true,
+			updates: []tablePathValue{
+				createIntervalTickerUpdate(), // no value change, suppressed
+				createIntervalTickerUpdate(), // heartbeat
+				createIntervalTickerUpdate(), // no value change, suppressed
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+			},
+		},
+		{
+			desc:              "(suppress redundant) sample stream query for table key Ethernet* with heartbeat every interval",
+			q:                 createCountersDbQueryHeartbeat(t, pb.SubscriptionMode_SAMPLE, sdc.MinSampleInterval, "COUNTERS", "Ethernet*"),
+			generateIntervals: true,
+			updates: []tablePathValue{
+				createIntervalTickerUpdate(), // heartbeat
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet*"}, TS: time.Unix(0, 200), Val: countersEthernetWildcardJson},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet*"}, TS: time.Unix(0, 200), Val: countersEthernetWildcardJson},
+			},
+		},
+		{
+			desc:              "(heartbeat) stream query for table key Ethernet68 with no change",
+			q:                 createCountersDbQueryHeartbeat(t, pb.SubscriptionMode_ON_CHANGE, 2*sdc.MinSampleInterval, "COUNTERS", "Ethernet68"),
+			generateIntervals: true,
+			updates: []tablePathValue{
+				createIntervalTickerUpdate(), // heartbeat
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68"}, TS: time.Unix(0, 200), Val: countersEthernet68Json},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68"}, TS: time.Unix(0, 200), Val: countersEthernet68Json},
+			},
+		},
 		/*
 			// deletion of field from table is not supported. It'd keep sending the last value before the deletion.
 				{
@@ -2211,6 +2928,9 @@ func runTestSubscribe(t *testing.T, namespace string) {
 					rclient.HDel(update.tableName+update.delimitor+update.tableKey, update.field)
 				case "intervaltick":
 					// This is not a DB update but a request to trigger sample interval
+				case "reconnect":
+					rclient.ClientKillByFilter("TYPE", "pubsub")
+					rclient.HSet(update.tableName+update.delimitor+update.tableKey, update.field, update.value)
 				default:
 					rclient.HSet(update.tableName+update.delimitor+update.tableKey, update.field, update.value)
 				}
@@ -2280,6 +3000,1632 @@ func TestGnmiSubscribeMultiNs(t *testing.T) {
 	s.s.Stop()
 }
 
+func TestAuthzPolicy(t *testing.T) {
+	policyJson := `{"rules": [
+		{"name": "admin", "roles": ["admin"], "targets": ["*"], "operations": ["read", "write", "subscribe"]},
+		{"name": "netops", "roles": ["netops"], "targets": ["COUNTERS_DB", "STATE_DB"], "operations": ["read", "subscribe"]},
+		{"name": "ports", "roles": ["netops"], "targets": ["CONFIG_DB"], "paths": ["/PORT", "/VLAN[name=Vlan100]"], "operations": ["read"]}
+	]}`
+	f, err := ioutil.TempFile("", "authz_policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(policyJson); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	policy, err := LoadAuthzPolicyFile(f.Name())
+	if err != nil {
+		t.Fatalf("LoadAuthzPolicyFile failed: %v", err)
+	}
+	s := &Server{config: &Config{AuthzPolicy: policy}}
+
+	tds := []struct {
+		desc        string
+		roles       []string
+		op          AuthzOp
+		target      string
+		path        string
+		wantRetCode codes.Code
+	}{
+		{
+			desc:        "admin writes CONFIG_DB",
+			roles:       []string{"admin"},
+			op:          AuthzWrite,
+			target:      "CONFIG_DB",
+			path:        "/PORT/Ethernet0",
+			wantRetCode: codes.OK,
+		}, {
+			desc:        "netops subscribes COUNTERS_DB of a namespace",
+			roles:       []string{"users", "netops"},
+			op:          AuthzSubscribe,
+			target:      "COUNTERS_DB/asic0",
+			path:        "/COUNTERS/Ethernet*",
+			wantRetCode: codes.OK,
+		}, {
+			desc:        "netops reads PORT table",
+			roles:       []string{"netops"},
+			op:          AuthzRead,
+			target:      "CONFIG_DB",
+			path:        "/PORT/Ethernet0/mtu",
+			wantRetCode: codes.OK,
+		}, {
+			desc:        "netops reads the granted VLAN",
+			roles:       []string{"netops"},
+			op:          AuthzRead,
+			target:      "CONFIG_DB",
+			path:        "/VLAN[name=Vlan100]/members",
+			wantRetCode: codes.OK,
+		}, {
+			desc:        "netops reads another VLAN",
+			roles:       []string{"netops"},
+			op:          AuthzRead,
+			target:      "CONFIG_DB",
+			path:        "/VLAN[name=Vlan200]",
+			wantRetCode: codes.PermissionDenied,
+		}, {
+			desc:        "netops reads a table sharing the prefix of PORT",
+			roles:       []string{"netops"},
+			op:          AuthzRead,
+			target:      "CONFIG_DB",
+			path:        "/PORTCHANNEL",
+			wantRetCode: codes.PermissionDenied,
+		}, {
+			desc:        "netops writes PORT table",
+			roles:       []string{"netops"},
+			op:          AuthzWrite,
+			target:      "CONFIG_DB",
+			path:        "/PORT/Ethernet0",
+			wantRetCode: codes.PermissionDenied,
+		}, {
+			desc:        "user without roles reads COUNTERS_DB",
+			op:          AuthzRead,
+			target:      "COUNTERS_DB",
+			path:        "/COUNTERS",
+			wantRetCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, td := range tds {
+		t.Run(td.desc, func(t *testing.T) {
+			rc, ctx := common_utils.GetContext(context.Background())
+			rc.Auth = common_utils.AuthInfo{User: "user", AuthEnabled: true, Roles: td.roles}
+			path, err := xpath.ToGNMIPath(td.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = s.authorize(ctx, td.op, &pb.Path{Target: td.target}, []*pb.Path{path})
+			if got := status.Code(err); got != td.wantRetCode {
+				t.Fatalf("got return code %v, want %v: %v", got, td.wantRetCode, err)
+			}
+		})
+	}
+}
+
+// memSink keeps audit records in memory.
+type memSink struct {
+	mu      sync.Mutex
+	records []audit.Record
+}
+
+func (m *memSink) Write(r *audit.Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, *r)
+	return nil
+}
+
+func (m *memSink) Close() error {
+	return nil
+}
+
+func (m *memSink) waitRecords(n int) []audit.Record {
+	for i := 0; i < 50; i++ {
+		m.mu.Lock()
+		records := append([]audit.Record{}, m.records...)
+		m.mu.Unlock()
+		if len(records) >= n {
+			return records
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
+
+func TestAuditLog(t *testing.T) {
+	sink := &memSink{}
+	s := createServerWithConfig(t, &Config{Port: 8081, Audit: audit.NewLogger(sink)})
+	go runServer(t, s)
+	defer s.s.Stop()
+
+	prepareDb(t, sdcfg.GetDbDefaultNamespace())
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+
+	targetAddr := "127.0.0.1:8081"
+	conn, err := grpc.Dial(targetAddr, opts...)
+	if err != nil {
+		t.Fatalf("Dialing to %q failed: %v", targetAddr, err)
+	}
+	defer conn.Close()
+
+	gClient := pb.NewGNMIClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, table := range []string{"COUNTERS_PORT_NAME_MAP", "NO_SUCH_TABLE"} {
+		req := &pb.GetRequest{
+			Prefix:   &pb.Path{Target: "COUNTERS_DB"},
+			Path:     []*pb.Path{{Elem: []*pb.PathElem{{Name: table}}}},
+			Encoding: pb.Encoding_JSON_IETF,
+		}
+		gClient.Get(ctx, req)
+	}
+
+	stream, err := gClient.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	err = stream.Send(&pb.SubscribeRequest{
+		Request: &pb.SubscribeRequest_Subscribe{
+			Subscribe: &pb.SubscriptionList{
+				Prefix: &pb.Path{Target: "COUNTERS_DB"},
+				Mode:   pb.SubscriptionList_ONCE,
+				Subscription: []*pb.Subscription{
+					{Path: &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS_PORT_NAME_MAP"}}}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Sending subscription failed: %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+
+	records := sink.waitRecords(4)
+	if len(records) != 4 {
+		t.Fatalf("got %d audit records, want 4: %v", len(records), records)
+	}
+	want := []audit.Record{
+		{Operation: "/gnmi.gNMI/Get", Target: "COUNTERS_DB", Paths: []string{"/COUNTERS_PORT_NAME_MAP"}, Code: "OK"},
+		{Operation: "/gnmi.gNMI/Get", Target: "COUNTERS_DB", Paths: []string{"/NO_SUCH_TABLE"}, Code: "NotFound"},
+		{Operation: "/gnmi.gNMI/Subscribe", Event: "start", Target: "COUNTERS_DB", Paths: []string{"/COUNTERS_PORT_NAME_MAP"}},
+		{Operation: "/gnmi.gNMI/Subscribe", Event: "stop", Target: "COUNTERS_DB", Paths: []string{"/COUNTERS_PORT_NAME_MAP"}, Code: "OK"},
+	}
+	for i, r := range records {
+		if r.RequestID == "" || r.Peer == "" {
+			t.Errorf("record %d misses request ID or peer: %v", i, r)
+		}
+		got := audit.Record{Operation: r.Operation, Event: r.Event, Target: r.Target, Paths: r.Paths, Code: r.Code}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("got record %d %v, want %v", i, got, want[i])
+		}
+	}
+	if records[2].RequestID != records[3].RequestID {
+		t.Errorf("start and stop of a subscription have different request IDs: %v, %v", records[2].RequestID, records[3].RequestID)
+	}
+}
+
+func TestAdminSessions(t *testing.T) {
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	defer s.s.Stop()
+
+	prepareDb(t, sdcfg.GetDbDefaultNamespace())
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+
+	targetAddr := "127.0.0.1:8081"
+	conn, err := grpc.Dial(targetAddr, opts...)
+	if err != nil {
+		t.Fatalf("Dialing to %q failed: %v", targetAddr, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := pb.NewGNMIClient(conn).Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	err = stream.Send(&pb.SubscribeRequest{
+		Request: &pb.SubscribeRequest_Subscribe{
+			Subscribe: &pb.SubscriptionList{
+				Prefix: &pb.Path{Target: "COUNTERS_DB"},
+				Mode:   pb.SubscriptionList_STREAM,
+				Subscription: []*pb.Subscription{
+					{Path: &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS_PORT_NAME_MAP"}}}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Sending subscription failed: %v", err)
+	}
+	// Wait for the initial sync.
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Receiving subscription updates failed: %v", err)
+		}
+		if resp.GetSyncResponse() {
+			break
+		}
+	}
+
+	ac := spb_admin.NewSonicAdminServiceClient(conn)
+	list, err := ac.ListSessions(ctx, &spb_admin.ListSessionsRequest{})
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(list.GetSessions()) != 1 {
+		t.Fatalf("got %d sessions, want 1: %v", len(list.GetSessions()), list)
+	}
+	session := list.GetSessions()[0]
+	if session.GetMode() != "STREAM" || session.GetTarget() != "COUNTERS_DB" ||
+		!reflect.DeepEqual(session.GetPaths(), []string{"/COUNTERS_PORT_NAME_MAP"}) {
+		t.Errorf("got session %v, want a STREAM session of COUNTERS_DB /COUNTERS_PORT_NAME_MAP", session)
+	}
+	if session.GetId() == "" || session.GetPeer() == "" || session.GetSentMsgs() < 2 || session.GetRecvMsgs() < 1 {
+		t.Errorf("got session %v, want id, peer and message counters", session)
+	}
+
+	got, err := ac.GetSession(ctx, &spb_admin.GetSessionRequest{Id: session.GetId()})
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if got.GetSession().GetId() != session.GetId() || got.GetSession().GetPeer() != session.GetPeer() {
+		t.Errorf("got session %v, want %v", got.GetSession(), session)
+	}
+	if _, err = ac.GetSession(ctx, &spb_admin.GetSessionRequest{Id: "NO_SUCH_SESSION"}); status.Code(err) != codes.NotFound {
+		t.Errorf("got return code %v for an unknown session, want %v", status.Code(err), codes.NotFound)
+	}
+
+	if _, err = ac.TerminateSession(ctx, &spb_admin.TerminateSessionRequest{Id: session.GetId()}); err != nil {
+		t.Fatalf("TerminateSession failed: %v", err)
+	}
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+	}
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("got return code %v for the terminated session, want %v: %v", status.Code(err), codes.Aborted, err)
+	}
+}
+
+func TestServerShutdown(t *testing.T) {
+	s := createServer(t, 8082)
+	go runServer(t, s)
+
+	prepareDb(t, sdcfg.GetDbDefaultNamespace())
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+
+	targetAddr := "127.0.0.1:8082"
+	conn, err := grpc.Dial(targetAddr, opts...)
+	if err != nil {
+		t.Fatalf("Dialing to %q failed: %v", targetAddr, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	gc := pb.NewGNMIClient(conn)
+	stream, err := gc.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	err = stream.Send(&pb.SubscribeRequest{
+		Request: &pb.SubscribeRequest_Subscribe{
+			Subscribe: &pb.SubscriptionList{
+				Prefix: &pb.Path{Target: "COUNTERS_DB"},
+				Mode:   pb.SubscriptionList_STREAM,
+				Subscription: []*pb.Subscription{
+					{Path: &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS_PORT_NAME_MAP"}}}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Sending subscription failed: %v", err)
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Receiving subscription updates failed: %v", err)
+		}
+		if resp.GetSyncResponse() {
+			break
+		}
+	}
+
+	if err = s.Shutdown(5 * time.Second); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+	}
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("got return code %v for the session at shutdown, want %v: %v", status.Code(err), codes.Unavailable, err)
+	}
+
+	req := &pb.GetRequest{
+		Prefix:   &pb.Path{Target: "COUNTERS_DB"},
+		Path:     []*pb.Path{{Elem: []*pb.PathElem{{Name: "COUNTERS_PORT_NAME_MAP"}}}},
+		Encoding: pb.Encoding_JSON_IETF,
+	}
+	if _, err = gc.Get(ctx, req); status.Code(err) != codes.Unavailable {
+		t.Errorf("got return code %v for a Get after shutdown, want %v: %v", status.Code(err), codes.Unavailable, err)
+	}
+}
+
+func TestListeners(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnmi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sink := &memSink{}
+	socket := filepath.Join(dir, "gnmi.sock")
+	cfg := &Config{
+		Addrs:      []string{"127.0.0.1:8083", "127.0.0.1:8084"},
+		UnixSocket: socket,
+		Audit:      audit.NewLogger(sink),
+	}
+	s := createServerWithConfig(t, cfg)
+	go runServer(t, s)
+	defer s.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	for _, targetAddr := range cfg.Addrs {
+		conn, err := grpc.Dial(targetAddr, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+		if err != nil {
+			t.Fatalf("Dialing to %q failed: %v", targetAddr, err)
+		}
+		defer conn.Close()
+		if _, err = spb_admin.NewSonicAdminServiceClient(conn).ListSessions(ctx, &spb_admin.ListSessionsRequest{}); err != nil {
+			t.Errorf("ListSessions on %v failed: %v", targetAddr, err)
+		}
+	}
+
+	conn, err := grpc.Dial("unix:"+socket, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Dialing to %q failed: %v", socket, err)
+	}
+	defer conn.Close()
+	if _, err = spb_admin.NewSonicAdminServiceClient(conn).ListSessions(ctx, &spb_admin.ListSessionsRequest{}); err != nil {
+		t.Fatalf("ListSessions on %v failed: %v", socket, err)
+	}
+
+	usr, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := sink.waitRecords(3)
+	if len(records) != 3 {
+		t.Fatalf("got %d audit records, want 3: %v", len(records), records)
+	}
+	r := records[2]
+	if r.User != usr.Username || !strings.HasPrefix(r.Peer, fmt.Sprintf("pid=%d,uid=%s,", os.Getpid(), usr.Uid)) {
+		t.Errorf("got user %q peer %q for the unix socket client, want user %q of pid %d", r.User, r.Peer, usr.Username, os.Getpid())
+	}
+
+	// Shutdown returns once the unix socket listener is stopped too.
+	if err = s.Shutdown(5 * time.Second); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if _, err = spb_admin.NewSonicAdminServiceClient(conn).ListSessions(ctx, &spb_admin.ListSessionsRequest{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("got return code %v for ListSessions on %v after shutdown, want %v: %v", status.Code(err), socket, codes.Unavailable, err)
+	}
+}
+
+// writeCert writes a test certificate and its key as PEM files.
+func writeCert(t *testing.T, certFile, keyFile string) tls.Certificate {
+	certificate, err := testcert.NewCert()
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate[0]})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(certificate.PrivateKey.(*rsa.PrivateKey))})
+	if err := ioutil.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certificate
+}
+
+func TestTLSReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnmi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	caFile := filepath.Join(dir, "ca.crt")
+	first := writeCert(t, certFile, keyFile)
+	caPEM, _ := ioutil.ReadFile(certFile)
+	ioutil.WriteFile(caFile, caPEM, 0600)
+
+	r, err := NewTLSReloader(&tls.Config{ClientAuth: tls.RequireAndVerifyClientCert}, certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("NewTLSReloader failed: %v", err)
+	}
+	config := r.Config()
+	served := func() *tls.Config {
+		c, err := config.GetConfigForClient(&tls.ClientHelloInfo{})
+		if err != nil {
+			t.Fatalf("GetConfigForClient failed: %v", err)
+		}
+		return c
+	}
+	c := served()
+	if !bytes.Equal(c.Certificates[0].Certificate[0], first.Certificate[0]) || c.ClientCAs == nil ||
+		c.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("got certificates %v client CAs %v, want the loaded files", c.Certificates, c.ClientCAs)
+	}
+
+	// Unchanged files are not reloaded.
+	if err = r.Reload(); err != nil {
+		t.Errorf("Reload of unchanged files failed: %v", err)
+	}
+	if served() != c {
+		t.Errorf("got unchanged files reloaded")
+	}
+
+	second := writeCert(t, certFile, keyFile)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, later, later)
+	os.Chtimes(keyFile, later, later)
+	if err = r.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if c = served(); !bytes.Equal(c.Certificates[0].Certificate[0], second.Certificate[0]) {
+		t.Errorf("got the old certificate after reload")
+	}
+
+	// A broken key keeps the certificate in use.
+	ioutil.WriteFile(keyFile, []byte("broken"), 0600)
+	later = later.Add(time.Minute)
+	os.Chtimes(keyFile, later, later)
+	if err = r.Reload(); err == nil {
+		t.Errorf("Reload of a broken key succeeded")
+	}
+	if c = served(); !bytes.Equal(c.Certificates[0].Certificate[0], second.Certificate[0]) {
+		t.Errorf("got the certificate replaced by a broken key pair")
+	}
+}
+
+func TestJwtKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnmi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer GenerateJwtSecretKey()
+
+	keyFile := filepath.Join(dir, "jwt_keys.json")
+	writeKeys := func(perm os.FileMode, secrets ...string) {
+		var keys []string
+		for i, secret := range secrets {
+			keys = append(keys, fmt.Sprintf(`{"kid": "k%d", "secret": "%s"}`, i+1,
+				base64.StdEncoding.EncodeToString([]byte(secret))))
+		}
+		ioutil.WriteFile(keyFile, []byte(`{"keys": [`+strings.Join(keys, ",")+`]}`), perm)
+		os.Chmod(keyFile, perm)
+	}
+	verify := func(tokenString string) (string, error) {
+		claims := &Claims{}
+		token, err := jwt.ParseWithClaims(tokenString, claims, jwtKeyFunc)
+		if err != nil {
+			return "", err
+		}
+		return token.Header["kid"].(string), nil
+	}
+	expire := time.Now().Add(time.Hour)
+
+	writeKeys(0600, "first secret key")
+	if err = LoadJwtKeyFile(keyFile); err != nil {
+		t.Fatalf("LoadJwtKeyFile failed: %v", err)
+	}
+	first := generateJWT("admin", nil, expire)
+	if kid, err := verify(first); err != nil || kid != "k1" {
+		t.Errorf("got kid %v error %v, want k1", kid, err)
+	}
+
+	// Tokens signed by the old key stay valid after rotation.
+	writeKeys(0600, "first secret key", "second secret key")
+	if err = LoadJwtKeyFile(keyFile); err != nil {
+		t.Fatalf("LoadJwtKeyFile failed: %v", err)
+	}
+	second := generateJWT("admin", nil, expire)
+	if kid, err := verify(second); err != nil || kid != "k2" {
+		t.Errorf("got kid %v error %v, want k2", kid, err)
+	}
+	if _, err := verify(first); err != nil {
+		t.Errorf("got token of the old key rejected after rotation: %v", err)
+	}
+
+	// Invalid files keep the keys in use.
+	for _, invalid := range []struct {
+		perm    os.FileMode
+		secrets []string
+	}{
+		{0644, []string{"second secret key"}},
+		{0600, []string{"short"}},
+		{0600, nil},
+	} {
+		writeKeys(invalid.perm, invalid.secrets...)
+		if err = LoadJwtKeyFile(keyFile); err == nil {
+			t.Errorf("LoadJwtKeyFile of mode %v secrets %v succeeded", invalid.perm, invalid.secrets)
+		}
+	}
+	if _, err := verify(first); err != nil {
+		t.Errorf("got token rejected after invalid key files: %v", err)
+	}
+
+	// Tokens of removed keys are rejected.
+	writeKeys(0600, "third secret key")
+	if err = LoadJwtKeyFile(keyFile); err != nil {
+		t.Fatalf("LoadJwtKeyFile failed: %v", err)
+	}
+	if _, err := verify(first); err == nil {
+		t.Errorf("got token of a removed key accepted")
+	}
+}
+
+func TestJwtAsymmetricKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnmi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer GenerateJwtSecretKey()
+	JwtJwksFile = filepath.Join(dir, "jwks.json")
+	defer func() { JwtJwksFile = "" }()
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDer, _ := x509.MarshalECPrivateKey(ecKey)
+	rsaFile := filepath.Join(dir, "rsa.pem")
+	ecFile := filepath.Join(dir, "ec.pem")
+	ioutil.WriteFile(rsaFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}), 0600)
+	ioutil.WriteFile(ecFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDer}), 0600)
+
+	keyFile := filepath.Join(dir, "jwt_keys.json")
+	ioutil.WriteFile(keyFile, []byte(fmt.Sprintf(`{"keys": [
+		{"kid": "hs", "secret": "%s"},
+		{"kid": "rs", "alg": "RS256", "private_key_file": "%s"},
+		{"kid": "es", "alg": "ES256", "private_key_file": "%s"}]}`,
+		base64.StdEncoding.EncodeToString([]byte("first secret key")), rsaFile, ecFile)), 0600)
+	if err = LoadJwtKeyFile(keyFile); err != nil {
+		t.Fatalf("LoadJwtKeyFile failed: %v", err)
+	}
+
+	tokenString := generateJWT("admin", []string{"admin"}, time.Now().Add(time.Hour))
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, jwtKeyFunc)
+	if err != nil || token.Method != jwt.SigningMethodES256 || token.Header["kid"] != "es" {
+		t.Fatalf("got token %v error %v, want ES256 signed by es", token, err)
+	}
+
+	// The published keys verify the tokens.
+	var jwks struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	data, _ := ioutil.ReadFile(JwtJwksFile)
+	if err = json.Unmarshal(data, &jwks); err != nil {
+		t.Fatalf("invalid JWKS file %s: %v", data, err)
+	}
+	if len(jwks.Keys) != 2 || jwks.Keys[0]["kid"] != "rs" || jwks.Keys[0]["kty"] != "RSA" ||
+		jwks.Keys[1]["kid"] != "es" || jwks.Keys[1]["crv"] != "P-256" {
+		t.Fatalf("got JWKS %s, want the rs and es public keys", data)
+	}
+	coord := func(s string) *big.Int {
+		b, _ := base64.RawURLEncoding.DecodeString(s)
+		return new(big.Int).SetBytes(b)
+	}
+	published := &ecdsa.PublicKey{Curve: elliptic.P256(), X: coord(jwks.Keys[1]["x"]), Y: coord(jwks.Keys[1]["y"])}
+	if _, err = jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return published, nil }); err != nil {
+		t.Errorf("got token rejected by the published key: %v", err)
+	}
+	if rpc, err := (&Server{config: &Config{UserAuth: AuthTypes{"jwt": true}}}).PublicKeys(context.Background(),
+		&sgpb_jwt.PublicKeysRequest{}); err != nil || rpc.Jwks != string(data) {
+		t.Errorf("got PublicKeys %v error %v, want %s", rpc, err, data)
+	}
+
+	// Tokens must use the algorithm of their key.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{Username: "admin"})
+	forged.Header["kid"] = "rs"
+	forgedString, _ := forged.SignedString(x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey))
+	if _, err = jwt.ParseWithClaims(forgedString, &Claims{}, jwtKeyFunc); err == nil {
+		t.Errorf("got HS256 token of an RS256 key accepted")
+	}
+
+	os.Chmod(ecFile, 0644)
+	if err = LoadJwtKeyFile(keyFile); err == nil {
+		t.Errorf("LoadJwtKeyFile of a private key readable by others succeeded")
+	}
+}
+
+func TestAdminRole(t *testing.T) {
+	// Without authorization policy, only the admin role manages the server.
+	s := &Server{config: &Config{UserAuth: AuthTypes{"jwt": true}}, clients: map[string]*Client{}}
+	tokenCtx := func(token *sgpb_jwt.JwtToken) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("access_token", token.AccessToken))
+	}
+	operator := tokenCtx(tokenResp("operator", []string{"operator"}))
+	if _, err := s.ListSessions(operator, &spb_admin.ListSessionsRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got ListSessions of a non-admin user %v, want PermissionDenied", err)
+	}
+	if _, err := s.ListLockouts(operator, &spb_admin.ListLockoutsRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got ListLockouts of a non-admin user %v, want PermissionDenied", err)
+	}
+	if _, err := s.Revoke(operator, &sgpb_jwt.RevokeRequest{Username: "admin"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got Revoke of another user by a non-admin user %v, want PermissionDenied", err)
+	}
+	admin := tokenCtx(tokenResp("admin", []string{AdminRole}))
+	if _, err := s.ListSessions(admin, &spb_admin.ListSessionsRequest{}); err != nil {
+		t.Errorf("got ListSessions of an admin user rejected: %v", err)
+	}
+}
+
+func TestJwtRevoke(t *testing.T) {
+	defer func(valid time.Duration) { JwtValidInt = valid }(JwtValidInt)
+	JwtValidInt = time.Hour
+	policy := &AuthzPolicy{Rules: []AuthzRule{{Roles: []string{"admin"}, Targets: []string{"*"}, Operations: []AuthzOp{AuthzAdmin}}}}
+	if err := policy.init(); err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{config: &Config{UserAuth: AuthTypes{"jwt": true}, AuthzPolicy: policy}}
+	tokenCtx := func(token *sgpb_jwt.JwtToken) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("access_token", token.AccessToken))
+	}
+	valid := func(token *sgpb_jwt.JwtToken) bool {
+		_, _, err := JwtAuthenAndAuthor(tokenCtx(token))
+		return err == nil
+	}
+
+	// Logout revokes the token of the request only.
+	first := tokenResp("operator", []string{"operator"})
+	second := tokenResp("operator", []string{"operator"})
+	if _, err := s.Revoke(tokenCtx(first), &sgpb_jwt.RevokeRequest{}); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if valid(first) || !valid(second) {
+		t.Errorf("got tokens valid %v and %v after logout, want false and true", valid(first), valid(second))
+	}
+	if _, err := s.Revoke(tokenCtx(first), &sgpb_jwt.RevokeRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("got Revoke with a revoked token %v, want Unauthenticated", err)
+	}
+
+	// Only admins revoke the tokens of other users.
+	admin := tokenResp("admin", []string{"admin"})
+	if _, err := s.Revoke(tokenCtx(second), &sgpb_jwt.RevokeRequest{Username: "admin"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got Revoke of the admin tokens by an operator %v, want PermissionDenied", err)
+	}
+	if _, err := s.Revoke(tokenCtx(admin), &sgpb_jwt.RevokeRequest{Username: "operator"}); err != nil {
+		t.Fatalf("Revoke of the operator tokens failed: %v", err)
+	}
+	if valid(second) || !valid(admin) {
+		t.Errorf("got tokens valid %v and %v after revoking the operator, want false and true", valid(second), valid(admin))
+	}
+
+	// Tokens issued after the revocation are valid, even in the same second.
+	if third := tokenResp("operator", []string{"operator"}); !valid(third) {
+		t.Errorf("got token issued after the revocation rejected")
+	}
+}
+
+func TestJwtOidc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnmi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() { jwtOidc = nil }()
+
+	idpKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwks := &jwtKeySet{Keys: []*jwtKey{{Kid: "idp", Alg: "ES256", signKey: idpKey}}}
+	if err = jwks.init(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := jwks.jwks()
+	jwksFile := filepath.Join(dir, "idp_jwks.json")
+	ioutil.WriteFile(jwksFile, data, 0644)
+	configFile := filepath.Join(dir, "oidc.json")
+	ioutil.WriteFile(configFile, []byte(`{"issuer": "https://idp.example.com", "audience": "sonic",
+		"jwks_file": "`+jwksFile+`", "username_claim": "preferred_username", "roles_claim": "groups",
+		"role_map": {"netadmins": "admin", "netops": "operator"}}`), 0644)
+	if err = LoadJwtOidcConfig(configFile); err != nil {
+		t.Fatalf("LoadJwtOidcConfig failed: %v", err)
+	}
+
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) context.Context {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = "idp"
+		tokenString, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("access_token", tokenString))
+	}
+	claims := func(changes jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":                "https://idp.example.com",
+			"aud":                []string{"sonic", "other"},
+			"exp":                time.Now().Add(time.Hour).Unix(),
+			"iat":                time.Now().Unix(),
+			"preferred_username": "alice",
+			"groups":             []string{"netops", "staff"},
+		}
+		for k, v := range changes {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+
+	_, ctx, err := JwtAuthenAndAuthor(sign(jwt.SigningMethodES256, idpKey, claims(nil)))
+	if err != nil {
+		t.Fatalf("got token of the issuer rejected: %v", err)
+	}
+	rc, _ := common_utils.GetContext(ctx)
+	if rc.Auth.User != "alice" || !reflect.DeepEqual(rc.Auth.Roles, []string{"operator"}) {
+		t.Errorf("got user %v roles %v, want alice and the operator role", rc.Auth.User, rc.Auth.Roles)
+	}
+
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	for name, ctx := range map[string]context.Context{
+		"other audience": sign(jwt.SigningMethodES256, idpKey, claims(jwt.MapClaims{"aud": "other"})),
+		"other issuer":   sign(jwt.SigningMethodES256, idpKey, claims(jwt.MapClaims{"iss": "https://other.example.com"})),
+		"expired":        sign(jwt.SigningMethodES256, idpKey, claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})),
+		"no expiry":      sign(jwt.SigningMethodES256, idpKey, claims(jwt.MapClaims{"exp": nil})),
+		"long lifetime":  sign(jwt.SigningMethodES256, idpKey, claims(jwt.MapClaims{"exp": time.Now().Add(48 * time.Hour).Unix()})),
+		"no role":        sign(jwt.SigningMethodES256, idpKey, claims(jwt.MapClaims{"groups": []string{"staff"}})),
+		"no user":        sign(jwt.SigningMethodES256, idpKey, claims(jwt.MapClaims{"preferred_username": nil})),
+		"other key":      sign(jwt.SigningMethodES256, otherKey, claims(nil)),
+		"HS256":          sign(jwt.SigningMethodHS256, []byte(data), claims(nil)),
+	} {
+		if _, _, err := JwtAuthenAndAuthor(ctx); err == nil {
+			t.Errorf("got token with %v accepted", name)
+		}
+	}
+
+	// Tokens of the server are still accepted.
+	if _, _, err := JwtAuthenAndAuthor(metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("access_token", generateJWT("bob", []string{"admin"}, time.Now().Add(time.Hour))))); err != nil {
+		t.Errorf("got token of the server rejected: %v", err)
+	}
+
+	// The revocations of users are kept as long as the OIDC tokens live.
+	defer func(valid time.Duration) { JwtValidInt = valid }(JwtValidInt)
+	JwtValidInt = time.Hour
+	d := &jwtDenylist{tokens: map[string]time.Time{}, users: map[string]time.Time{"alice": time.Now().Add(-2 * time.Hour)}}
+	d.prune(time.Now())
+	if _, ok := d.users["alice"]; !ok {
+		t.Errorf("got revocation of alice pruned before the OIDC tokens expire")
+	}
+}
+
+// countingBackend accepts the password "secret" and counts its checks.
+type countingBackend struct {
+	checks int
+}
+
+func (b *countingBackend) Authenticate(username, password string) error {
+	b.checks++
+	if password != "secret" {
+		return fmt.Errorf("invalid password")
+	}
+	return nil
+}
+
+func TestPasswordAuth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnmi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer SetPasswordAuth(&sshBackend{addr: "127.0.0.1:22"}, 0)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	htpasswd := filepath.Join(dir, "htpasswd")
+	ioutil.WriteFile(htpasswd, []byte("# users\nalice:"+string(hash)+"\n"), 0600)
+	backend, err := NewPasswordBackend("htpasswd", htpasswd)
+	if err != nil {
+		t.Fatalf("NewPasswordBackend failed: %v", err)
+	}
+	if err = backend.Authenticate("alice", "secret"); err != nil {
+		t.Errorf("got valid password rejected: %v", err)
+	}
+	if err = backend.Authenticate("alice", "wrong"); err == nil {
+		t.Errorf("got invalid password accepted")
+	}
+	if err = backend.Authenticate("bob", "secret"); err == nil {
+		t.Errorf("got unknown user accepted")
+	}
+
+	// The file is reloaded when it changes.
+	ioutil.WriteFile(htpasswd, []byte("bob:"+string(hash)+"\n"), 0600)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(htpasswd, later, later)
+	if err = backend.Authenticate("bob", "secret"); err != nil {
+		t.Errorf("got user added to the file rejected: %v", err)
+	}
+	if err = backend.Authenticate("alice", "secret"); err == nil {
+		t.Errorf("got user removed from the file accepted")
+	}
+
+	// Cached checks are forgotten when the file changes.
+	SetPasswordAuth(backend, time.Minute)
+	if ok, _ := UserPwAuth("bob", "secret"); !ok {
+		t.Errorf("got valid password rejected")
+	}
+	ioutil.WriteFile(htpasswd, []byte("alice:"+string(hash)+"\n"), 0600)
+	later = later.Add(time.Minute)
+	os.Chtimes(htpasswd, later, later)
+	if ok, _ := UserPwAuth("bob", "secret"); ok {
+		t.Errorf("got cached password of a user removed from the file accepted")
+	}
+
+	ioutil.WriteFile(htpasswd, []byte("alice:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=\n"), 0600)
+	if _, err = NewPasswordBackend("htpasswd", htpasswd); err == nil {
+		t.Errorf("got htpasswd file of SHA1 hashes accepted")
+	}
+	if _, err = NewPasswordBackend("ldap", ""); err == nil {
+		t.Errorf("got unknown password backend created")
+	}
+
+	// Successful checks only are cached.
+	counting := &countingBackend{}
+	SetPasswordAuth(counting, time.Minute)
+	for _, password := range []string{"secret", "secret", "wrong", "wrong"} {
+		ok, _ := UserPwAuth("alice", password)
+		if ok != (password == "secret") {
+			t.Errorf("got password %v accepted %v", password, ok)
+		}
+	}
+	if counting.checks != 3 {
+		t.Errorf("got %v backend checks, want 3", counting.checks)
+	}
+}
+
+func TestAuthLockout(t *testing.T) {
+	usr, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetPasswordAuth(&sshBackend{addr: "127.0.0.1:22"}, 0)
+	defer SetAuthLockout(AuthLockoutPolicy{}, nil)
+	counting := &countingBackend{}
+	SetPasswordAuth(counting, 0)
+	sink := &memSink{}
+	SetAuthLockout(AuthLockoutPolicy{MaxFailures: 3, Duration: time.Minute, MaxDuration: 10 * time.Minute}, audit.NewLogger(sink))
+
+	attempt := func(addr, username, password string) error {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 50000}})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("username", username, "password", password))
+		_, err := BasicAuthenAndAuthor(ctx)
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := attempt("10.0.0.1", usr.Username, "wrong"); status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("got attempt %d %v, want PermissionDenied", i, err)
+		}
+	}
+	// Locked out users are rejected without checking their password, from
+	// any address.
+	checks := counting.checks
+	if err := attempt("10.0.0.2", usr.Username, "secret"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("got attempt of a locked out user %v, want ResourceExhausted", err)
+	}
+	if err := attempt("10.0.0.1", "unknown-user", "secret"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("got attempt from a locked out address %v, want ResourceExhausted", err)
+	}
+	if counting.checks != checks {
+		t.Errorf("got passwords of locked out attempts checked")
+	}
+
+	lockouts := authLockouts.list()
+	if len(lockouts) != 2 || lockouts[0].Kind != "user" || lockouts[0].Name != usr.Username ||
+		lockouts[1].Kind != "address" || lockouts[1].Name != "10.0.0.1" {
+		t.Fatalf("got lockouts %v, want the user and 10.0.0.1", lockouts)
+	}
+	if until := time.Unix(0, lockouts[0].LockedUntil); time.Until(until) <= 0 || time.Until(until) > time.Minute {
+		t.Errorf("got user locked until %v, want within a minute", until)
+	}
+	records := sink.waitRecords(2)
+	if len(records) != 2 || records[0].Event != "lockout" || records[0].User != usr.Username || records[1].Peer != "10.0.0.1:50000" {
+		t.Errorf("got audit records %v, want the lockouts of the user and the address", records)
+	}
+
+	// Each failure after the lockout doubles it.
+	authLockouts.mu.Lock()
+	for _, st := range authLockouts.states {
+		st.lockedUntil = time.Now()
+	}
+	authLockouts.mu.Unlock()
+	if err := attempt("10.0.0.3", usr.Username, "wrong"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("got attempt after the lockout %v, want PermissionDenied", err)
+	}
+	if until := time.Unix(0, authLockouts.list()[0].LockedUntil); time.Until(until) <= time.Minute {
+		t.Errorf("got user locked until %v, want after a minute", until)
+	}
+
+	// Successes reset the failures of the user.
+	authLockouts.mu.Lock()
+	for _, st := range authLockouts.states {
+		st.lockedUntil = time.Now()
+	}
+	authLockouts.mu.Unlock()
+	if err := attempt("10.0.0.4", usr.Username, "secret"); err != nil {
+		t.Fatalf("got valid password rejected after the lockout: %v", err)
+	}
+	for _, lo := range authLockouts.list() {
+		if lo.Kind == "user" {
+			t.Errorf("got failures of the user kept after a success: %v", lo)
+		}
+	}
+
+	// Concurrent attempts are limited to the failures left before a lockout.
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := authLockouts.check(ctx, "bob"); err != nil {
+			t.Fatalf("got concurrent attempt %d rejected: %v", i, err)
+		}
+	}
+	if err := authLockouts.check(ctx, "bob"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("got attempt beyond the failures left %v, want ResourceExhausted", err)
+	}
+	for i := 0; i < 3; i++ {
+		authLockouts.record(ctx, "bob", false)
+	}
+	if err := authLockouts.check(ctx, "bob"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("got attempt of a locked out user %v, want ResourceExhausted", err)
+	}
+
+	// The users counted are bounded, the locked out ones being kept.
+	defer func(max int) { maxLockoutStates = max }(maxLockoutStates)
+	maxLockoutStates = 10
+	for i := 0; i < 20; i++ {
+		authLockouts.record(ctx, fmt.Sprintf("user%d", i), false)
+	}
+	authLockouts.mu.Lock()
+	_, bobKept := authLockouts.states[lockoutKey{"user", "bob"}]
+	count := len(authLockouts.states)
+	authLockouts.mu.Unlock()
+	if count > maxLockoutStates || !bobKept {
+		t.Errorf("got %d users counted, bob kept %v, want at most %d with bob", count, bobKept, maxLockoutStates)
+	}
+
+	// Attempts which cannot be counted are rejected, and users are
+	// forgotten before addresses.
+	SetAuthLockout(AuthLockoutPolicy{MaxFailures: 3, Duration: time.Minute, MaxDuration: 10 * time.Minute}, nil)
+	maxLockoutStates = 4
+	from := func(addr string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 50000}})
+	}
+	for i := 1; i <= 2; i++ {
+		if err := authLockouts.check(from(fmt.Sprintf("10.0.1.%d", i)), fmt.Sprintf("user%d", i)); err != nil {
+			t.Fatalf("got attempt %d rejected: %v", i, err)
+		}
+	}
+	if err := authLockouts.check(from("10.0.1.3"), "user3"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("got attempt beyond the states in flight %v, want ResourceExhausted", err)
+	}
+	for i := 1; i <= 2; i++ {
+		authLockouts.record(from(fmt.Sprintf("10.0.1.%d", i)), fmt.Sprintf("user%d", i), false)
+	}
+	if err := authLockouts.check(from("10.0.1.4"), "user4"); err != nil {
+		t.Fatalf("got attempt replacing failed users rejected: %v", err)
+	}
+	authLockouts.mu.Lock()
+	for _, addr := range []string{"10.0.1.1", "10.0.1.2", "10.0.1.4"} {
+		if _, ok := authLockouts.states[lockoutKey{"address", addr}]; !ok {
+			t.Errorf("got address %v forgotten, want the users forgotten first", addr)
+		}
+	}
+	authLockouts.mu.Unlock()
+}
+
+func TestClientCertAuth(t *testing.T) {
+	usr, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "gnmi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer SetClientCertIdentity([]string{"cn"})
+	defer func() { clientCertMappings = nil }()
+	defer func() { clientCrls.crls = nil }()
+
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	caDer, _ := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	ca, _ := x509.ParseCertificate(caDer)
+	spiffe, _ := url.Parse("spiffe://sonic/collector")
+	leafKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	leafDer, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "", OrganizationalUnit: []string{"collectors"}},
+		DNSNames:     []string{"collector.sonic"},
+		URIs:         []*url.URL{spiffe},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, ca, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, _ := x509.ParseCertificate(leafDer)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr:     &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 50000},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{leaf, ca}}}},
+	})
+	auth := func() (*common_utils.AuthInfo, error) {
+		ctx, err := ClientCertAuthenAndAuthor(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rc, _ := common_utils.GetContext(ctx)
+		return &rc.Auth, nil
+	}
+
+	// The certificate has no CN, the default identity.
+	if _, err := auth(); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("got certificate without CN %v, want Unauthenticated", err)
+	}
+	if err := SetClientCertIdentity([]string{"cn", "upn"}); err == nil {
+		t.Errorf("SetClientCertIdentity accepted an unknown source")
+	}
+
+	mapFile := filepath.Join(dir, "map.json")
+	ioutil.WriteFile(mapFile, []byte(`{
+		"spiffe://sonic/collector": {"user": "`+usr.Username+`", "roles": ["readonly"]},
+		"collectors": {"user": "root", "roles": ["admin"]}
+	}`), 0600)
+	if err := LoadClientCertMap(mapFile); err != nil {
+		t.Fatalf("LoadClientCertMap failed: %v", err)
+	}
+	for _, test := range []struct {
+		sources []string
+		user    string
+		roles   []string
+	}{
+		// The first mapped identity wins, even after unmapped ones.
+		{[]string{"san_dns", "san_uri", "ou"}, usr.Username, []string{"readonly"}},
+		{[]string{"ou", "san_uri"}, "root", []string{"admin"}},
+	} {
+		SetClientCertIdentity(test.sources)
+		a, err := auth()
+		if err != nil {
+			t.Errorf("got identity %v rejected: %v", test.sources, err)
+		} else if a.User != test.user || !reflect.DeepEqual(a.Roles, test.roles) {
+			t.Errorf("got identity %v user %v roles %v, want %v %v", test.sources, a.User, a.Roles, test.user, test.roles)
+		}
+	}
+	// Unmapped identities are local users.
+	SetClientCertIdentity([]string{"san_dns"})
+	if _, err := auth(); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("got unknown local user %v, want Unauthenticated", err)
+	}
+
+	SetClientCertIdentity([]string{"san_uri"})
+	crlDir := filepath.Join(dir, "crl")
+	os.Mkdir(crlDir, 0700)
+	writeCrl := func(name string, signer *ecdsa.PrivateKey, serials ...int64) {
+		var revoked []pkix.RevokedCertificate
+		for _, serial := range serials {
+			revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+		}
+		der, err := ca.CreateCRL(rand.Reader, signer, revoked, time.Now(), time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ioutil.WriteFile(filepath.Join(crlDir, name), pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), 0600)
+	}
+	writeCrl("ca.crl", caKey, 7)
+	if err := LoadClientCrlDir(crlDir); err != nil {
+		t.Fatalf("LoadClientCrlDir failed: %v", err)
+	}
+	if _, err := auth(); err != nil {
+		t.Errorf("got certificate not revoked rejected: %v", err)
+	}
+
+	// CRLs not signed by the issuer are ignored.
+	forgedKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	writeCrl("forged.crl", forgedKey, 42)
+	if err := LoadClientCrlDir(crlDir); err != nil {
+		t.Fatalf("LoadClientCrlDir failed: %v", err)
+	}
+	if _, err := auth(); err != nil {
+		t.Errorf("got certificate revoked by a forged CRL rejected: %v", err)
+	}
+
+	writeCrl("ca.crl", caKey, 7, 42)
+	if err := LoadClientCrlDir(crlDir); err != nil {
+		t.Fatalf("LoadClientCrlDir failed: %v", err)
+	}
+	if _, err := auth(); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("got revoked certificate %v, want Unauthenticated", err)
+	}
+	// Revoked certificates fail the TLS handshake too.
+	r, err := NewTLSReloader(&tls.Config{ClientAuth: tls.RequireAndVerifyClientCert}, "", "", "")
+	if err != nil {
+		t.Fatalf("NewTLSReloader failed: %v", err)
+	}
+	served, err := r.Config().GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("GetConfigForClient failed: %v", err)
+	}
+	if err := served.VerifyPeerCertificate([][]byte{leaf.Raw, ca.Raw}, [][]*x509.Certificate{{leaf, ca}}); err == nil {
+		t.Errorf("got revoked certificate accepted in the TLS handshake")
+	}
+
+	// Broken files keep the CRLs in use.
+	ioutil.WriteFile(filepath.Join(crlDir, "broken.crl"), []byte("broken"), 0600)
+	if err := LoadClientCrlDir(crlDir); err == nil {
+		t.Errorf("LoadClientCrlDir of a broken CRL succeeded")
+	}
+	if _, err := auth(); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("got revoked certificate %v after a broken CRL, want Unauthenticated", err)
+	}
+}
+
+// methodStream sets the method of a context, as grpc does for the RPCs.
+type methodStream struct {
+	method string
+}
+
+func (s *methodStream) Method() string               { return s.method }
+func (s *methodStream) SetHeader(metadata.MD) error  { return nil }
+func (s *methodStream) SendHeader(metadata.MD) error { return nil }
+func (s *methodStream) SetTrailer(metadata.MD) error { return nil }
+
+func TestAuthPolicy(t *testing.T) {
+	usr, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetPasswordAuth(&sshBackend{addr: "127.0.0.1:22"}, 0)
+	defer SetAuthPolicy(nil)
+	SetPasswordAuth(&countingBackend{}, 0)
+
+	dir, err := ioutil.TempDir("", "gnmi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	policyFile := filepath.Join(dir, "auth.json")
+	ioutil.WriteFile(policyFile, []byte(`{"rpcs": {"set": "cert+password", "subscribe": "cert|jwt", "/gnmi.gNMI/Get": "password", "gnoi_system": "cert", "default": "totp"}}`), 0600)
+	if _, err := LoadAuthPolicyFile(policyFile); err == nil {
+		t.Errorf("got policy with an unknown mechanism loaded")
+	}
+	ioutil.WriteFile(policyFile, []byte(`{"rpcs": {"set": "cert+password", "subscribe": "cert|jwt", "/gnmi.gNMI/Get": "password", "gnoi_system": "cert"}}`), 0600)
+	policy, err := LoadAuthPolicyFile(policyFile)
+	if err != nil {
+		t.Fatalf("LoadAuthPolicyFile failed: %v", err)
+	}
+	SetAuthPolicy(policy)
+	// The policy may only require the enabled mechanisms.
+	for _, modes := range []AuthTypes{{"password": true, "jwt": true}, {"none": true}} {
+		if err := CheckAuthPolicy(modes); err == nil {
+			t.Errorf("got policy accepted with client_auth %v", modes)
+		}
+	}
+	if err := CheckAuthPolicy(AuthTypes{"password": true, "jwt": true, "cert": true}); err != nil {
+		t.Errorf("got policy rejected with all the mechanisms enabled: %v", err)
+	}
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	certTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: usr.Username},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, _ := x509.CreateCertificate(rand.Reader, certTemplate, certTemplate, &key.PublicKey, key)
+	cert, _ := x509.ParseCertificate(der)
+	token := tokenResp(usr.Username, []string{"admin"})
+	other := "root"
+	if usr.Username == other {
+		other = "nobody"
+	}
+
+	call := func(method string, withCert bool, md ...string) (*common_utils.AuthInfo, error) {
+		p := &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 50000}}
+		if withCert {
+			p.AuthInfo = credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}}
+		}
+		ctx := peer.NewContext(context.Background(), p)
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(md...))
+		ctx = grpc.NewContextWithServerTransportStream(ctx, &methodStream{method})
+		ctx, err := authenticate(AuthTypes{"password": true, "jwt": true, "cert": true}, ctx)
+		if err != nil {
+			return nil, err
+		}
+		rc, _ := common_utils.GetContext(ctx)
+		return &rc.Auth, nil
+	}
+
+	for _, test := range []struct {
+		desc       string
+		method     string
+		withCert   bool
+		md         []string
+		mechanisms []string
+	}{
+		{"set with cert and password", "/gnmi.gNMI/Set", true, []string{"username", usr.Username, "password", "secret"}, []string{"cert", "password"}},
+		{"set with cert only", "/gnmi.gNMI/Set", true, nil, nil},
+		{"set with password only", "/gnmi.gNMI/Set", false, []string{"username", usr.Username, "password", "secret"}, nil},
+		{"set with cert and password of another user", "/gnmi.gNMI/Set", true, []string{"username", other, "password", "secret"}, nil},
+		{"subscribe with cert", "/gnmi.gNMI/Subscribe", true, nil, []string{"cert"}},
+		{"subscribe with jwt", "/gnmi.gNMI/Subscribe", false, []string{"access_token", token.AccessToken}, []string{"jwt"}},
+		{"subscribe with password", "/gnmi.gNMI/Subscribe", false, []string{"username", usr.Username, "password", "secret"}, nil},
+		{"get by method name with password", "/gnmi.gNMI/Get", false, []string{"username", usr.Username, "password", "secret"}, []string{"password"}},
+		{"gnoi system with jwt", "/gnoi.system.System/Time", false, []string{"access_token", token.AccessToken}, nil},
+		{"gnoi system with cert", "/gnoi.system.System/Time", true, nil, []string{"cert"}},
+		// RPCs without rule accept any mechanism, in the usual order.
+		{"capabilities with jwt and cert", "/gnmi.gNMI/Capabilities", true, []string{"access_token", token.AccessToken}, []string{"jwt"}},
+	} {
+		auth, err := call(test.method, test.withCert, test.md...)
+		if test.mechanisms == nil {
+			if status.Code(err) != codes.Unauthenticated {
+				t.Errorf("%v: got %v, want Unauthenticated", test.desc, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: got %v", test.desc, err)
+		} else if auth.User != usr.Username || !reflect.DeepEqual(auth.Mechanisms, test.mechanisms) {
+			t.Errorf("%v: got user %v mechanisms %v, want %v %v", test.desc, auth.User, auth.Mechanisms, usr.Username, test.mechanisms)
+		}
+	}
+	if _, err := call("/gnmi.gNMI/Set", true); err == nil || !strings.Contains(err.Error(), "requires cert+password") {
+		t.Errorf("got error %v, want the required mechanisms", err)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnmi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	want := Settings{
+		"port":                "8080",
+		"listen_addr":         "10.1.0.32:8080,[fc00::1]:8080",
+		"client_auth":         "password,jwt",
+		"min_sample_interval": "500ms",
+		"v":                   "2",
+	}
+	files := map[string]string{
+		"settings.yaml": `
+port: 8080
+listen_addr:
+  - 10.1.0.32:8080
+  - "[fc00::1]:8080"
+client_auth: password,jwt
+min_sample_interval: 500ms
+log_level: 2
+`,
+		"settings.json": `{"port": 8080, "listen_addr": ["10.1.0.32:8080", "[fc00::1]:8080"],
+			"client_auth": "password,jwt", "min_sample_interval": "500ms", "log_level": 2}`,
+	}
+	for name, content := range files {
+		fileName := filepath.Join(dir, name)
+		ioutil.WriteFile(fileName, []byte(content), 0600)
+		got, err := LoadSettingsFile(fileName)
+		if err != nil {
+			t.Fatalf("LoadSettingsFile(%v) failed: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got settings %v from %v, want %v", got, name, want)
+		}
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	port := fs.Int("port", -1, "")
+	listenAddr := fs.String("listen_addr", "", "")
+	auth := AuthTypes{"password": false, "cert": false, "jwt": false}
+	fs.Var(auth, "client_auth", "")
+	fs.Duration("min_sample_interval", time.Second, "")
+	fs.Int("v", 0, "")
+	if err = want.SetFlags(fs, map[string]bool{"port": true}); err != nil {
+		t.Fatalf("SetFlags failed: %v", err)
+	}
+	if *port != -1 || *listenAddr != want["listen_addr"] {
+		t.Errorf("got port %v listen_addr %v, want -1 and %v", *port, *listenAddr, want["listen_addr"])
+	}
+	// The CONFIG_DB client_auth replaces the one of the file.
+	if err = (Settings{"client_auth": "cert"}).SetFlags(fs, nil); err != nil {
+		t.Fatalf("SetFlags failed: %v", err)
+	}
+	if auth.Enabled("password") || auth.Enabled("jwt") || !auth.Enabled("cert") {
+		t.Errorf("got auth modes %v, want cert only", auth)
+	}
+	if err = (Settings{"port": "http"}).SetFlags(fs, nil); err == nil {
+		t.Errorf("SetFlags of an invalid port succeeded")
+	}
+
+	defer sdc.SetMinSampleInterval(sdc.MinSampleInterval)
+	s := &Server{config: &Config{Port: 8080, UserAuth: AuthTypes{"password": true}}}
+	old := Settings{"port": "8080", "client_auth": "password"}
+	s.UpdateSettings(old, Settings{
+		"port":                "9090",
+		"client_auth":         "cert,jwt",
+		"client_queue_limit":  "100",
+		"client_queue_policy": "drop-oldest",
+		"min_sample_interval": "200ms",
+	})
+	if s.config.Port != 8080 {
+		t.Errorf("got port changed to %v at runtime", s.config.Port)
+	}
+	if auth := s.userAuth(); auth.Enabled("password") || !auth.Enabled("cert") || !auth.Enabled("jwt") {
+		t.Errorf("got auth modes %v, want cert and jwt", auth)
+	}
+	if limit, policy := s.queueSettings(); limit != 100 || policy != QueueDropOldest {
+		t.Errorf("got queue limit %v policy %v, want 100 and %v", limit, policy, QueueDropOldest)
+	}
+	if sdc.MinSampleInterval != 200*time.Millisecond {
+		t.Errorf("got min sample interval %v, want 200ms", sdc.MinSampleInterval)
+	}
+}
+
+func TestTargetDefined(t *testing.T) {
+	for _, modes := range []string{
+		"COUNTERS_DB=sample:fast",
+		"COUNTERS_DB=poll",
+		"STATE_DB=on_change:10s",
+		"OTHERS/proc=on_change",
+		"COUNTERS_DB",
+	} {
+		if _, err := sdc.ParseTargetDefinedModes(modes); err == nil {
+			t.Errorf("ParseTargetDefinedModes(%q) succeeded", modes)
+		}
+	}
+	modes, err := sdc.ParseTargetDefinedModes("STATE_DB=on_change, COUNTERS_DB/COUNTERS=sample:5s,OTHERS=sample")
+	if err != nil {
+		t.Fatalf("ParseTargetDefinedModes failed: %v", err)
+	}
+	want := map[string]sdc.TargetDefinedMode{
+		"STATE_DB":             {Mode: pb.SubscriptionMode_ON_CHANGE},
+		"COUNTERS_DB/COUNTERS": {Mode: pb.SubscriptionMode_SAMPLE, SampleInterval: 5 * time.Second},
+		"OTHERS":               {Mode: pb.SubscriptionMode_SAMPLE},
+	}
+	if !reflect.DeepEqual(modes, want) {
+		t.Errorf("got modes %v, want %v", modes, want)
+	}
+
+	// TARGET_DEFINED subscriptions of OTHERS are sampled.
+	defer func() {
+		modes, _ := sdc.ParseTargetDefinedModes(sdc.DefaultTargetDefinedModes)
+		sdc.SetTargetDefinedModes(modes)
+	}()
+	s := &Server{config: &Config{}}
+	s.UpdateSettings(Settings{}, Settings{"target_defined": "OTHERS/proc=sample:1h"})
+	prefix := &pb.Path{Target: "OTHERS"}
+	path := &pb.Path{Elem: []*pb.PathElem{{Name: "proc"}, {Name: "uptime"}}}
+	c, err := sdc.NewNonDbClient([]*pb.Path{path}, prefix)
+	if err != nil {
+		t.Fatalf("NewNonDbClient failed: %v", err)
+	}
+	q := queue.NewPriorityQueue(1, false)
+	stop := make(chan struct{})
+	var w sync.WaitGroup
+	w.Add(1)
+	go c.StreamRun(q, stop, &w, &pb.SubscriptionList{
+		Mode:         pb.SubscriptionList_STREAM,
+		Subscription: []*pb.Subscription{{Path: path, Mode: pb.SubscriptionMode_TARGET_DEFINED}},
+	})
+	defer w.Wait()
+	defer close(stop)
+	var items []interface{}
+	for len(items) < 2 {
+		got, err := q.Get(1)
+		if err != nil {
+			t.Fatalf("queue Get failed: %v", err)
+		}
+		for _, item := range got {
+			items = append(items, item)
+		}
+	}
+	for _, item := range items {
+		if v := item.(sdc.Value); v.GetFatal() != "" {
+			t.Fatalf("got TARGET_DEFINED subscription rejected: %v", v.GetFatal())
+		}
+	}
+	if len(items) != 2 || items[0].(sdc.Value).GetVal() == nil || !items[1].(sdc.Value).GetSyncResponse() {
+		t.Errorf("got %v, want the sampled value and a sync response", items)
+	}
+}
+
+func TestSendQueuePolicy(t *testing.T) {
+	update := func(port string, ts int64) sdc.Value {
+		return sdc.Value{Value: &spb.Value{
+			Prefix:    &pb.Path{Target: "COUNTERS_DB"},
+			Path:      &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS"}, {Name: port}}},
+			Timestamp: ts,
+		}}
+	}
+	syncResponse := sdc.Value{Value: &spb.Value{Timestamp: 3, SyncResponse: true}}
+	values := []sdc.Value{
+		update("Ethernet0", 1), update("Ethernet4", 2), syncResponse,
+		update("Ethernet0", 4), update("Ethernet8", 5),
+	}
+
+	tds := []struct {
+		desc          string
+		policy        QueuePolicy
+		wantErr       error
+		wantTs        []int64
+		wantDropped   int64
+		wantConflated int64
+	}{
+		{
+			desc:          "conflate",
+			policy:        QueueConflate,
+			wantTs:        []int64{2, 3, 5},
+			wantDropped:   1,
+			wantConflated: 1,
+		}, {
+			desc:        "drop oldest",
+			policy:      QueueDropOldest,
+			wantTs:      []int64{3, 4, 5},
+			wantDropped: 2,
+		}, {
+			desc:    "disconnect",
+			policy:  QueueDisconnect,
+			wantErr: errQueueOverflow,
+			wantTs:  []int64{1, 2, 3},
+		},
+	}
+
+	for _, td := range tds {
+		t.Run(td.desc, func(t *testing.T) {
+			q := newSendQueue(2, td.policy)
+			var err error
+			for _, v := range values {
+				if err = q.put(v); err != nil {
+					break
+				}
+			}
+			if err != td.wantErr {
+				t.Fatalf("got error %v, want %v", err, td.wantErr)
+			}
+
+			depth, dropped, conflated := q.stats()
+			if dropped != td.wantDropped || conflated != td.wantConflated {
+				t.Errorf("got %d dropped and %d conflated updates, want %d and %d",
+					dropped, conflated, td.wantDropped, td.wantConflated)
+			}
+			var gotTs []int64
+			for i := int64(0); i < depth; i++ {
+				v, err := q.get()
+				if err != nil {
+					t.Fatalf("get failed: %v", err)
+				}
+				gotTs = append(gotTs, v.GetTimestamp())
+			}
+			if !reflect.DeepEqual(gotTs, td.wantTs) {
+				t.Errorf("got values with timestamps %v, want %v", gotTs, td.wantTs)
+			}
+		})
+	}
+
+	// The updates of different keys of a table are not conflated.
+	tableUpdate := func(key string, ts int64) sdc.Value {
+		return sdc.Value{Value: &spb.Value{
+			Prefix:    &pb.Path{Target: "APPL_DB"},
+			Path:      &pb.Path{Elem: []*pb.PathElem{{Name: "PORT_TABLE"}}},
+			Timestamp: ts,
+		}, Keys: []string{key}}
+	}
+	q := newSendQueue(2, QueueConflate)
+	for _, v := range []sdc.Value{tableUpdate("Ethernet0", 1), tableUpdate("Ethernet4", 2),
+		tableUpdate("Ethernet0", 3), tableUpdate("Ethernet8", 4)} {
+		if err := q.put(v); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+	var gotTs []int64
+	for depth, _, _ := q.stats(); depth > 0; depth-- {
+		v, _ := q.get()
+		gotTs = append(gotTs, v.GetTimestamp())
+	}
+	if _, dropped, conflated := q.stats(); !reflect.DeepEqual(gotTs, []int64{2, 4}) || dropped != 1 || conflated != 1 {
+		t.Errorf("got table updates %v with %d dropped and %d conflated, want [2 4] with 1 and 1", gotTs, dropped, conflated)
+	}
+
+	// Without limit, the updates are neither dropped nor indexed by path.
+	q = newSendQueue(0, QueueConflate)
+	for _, v := range values {
+		if err := q.put(v); err != nil {
+			t.Fatalf("put without limit failed: %v", err)
+		}
+	}
+	if depth, _, _ := q.stats(); depth != int64(len(values)) || len(q.pending) != 0 {
+		t.Errorf("got depth %d and %d pending paths without limit, want %d and 0", depth, len(q.pending), len(values))
+	}
+}
+
+func TestServerMetrics(t *testing.T) {
+	newClient := func(port int, mode pb.SubscriptionList_Mode, sent int64) *Client {
+		c := NewClient(&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port})
+		c.subscribe = &pb.SubscriptionList{Prefix: &pb.Path{Target: "COUNTERS_DB"}, Mode: mode}
+		c.sendMsg = sent
+		return c
+	}
+	s := &Server{clients: map[string]*Client{}, closed: map[sessionKey]*sessionStats{}}
+	closed := newClient(50001, pb.SubscriptionList_STREAM, 5)
+	s.clients[closed.String()] = closed
+	s.closeSession(closed)
+	if len(s.clients) != 0 {
+		t.Errorf("got %d clients after closeSession, want 0", len(s.clients))
+	}
+	for _, c := range []*Client{
+		newClient(50002, pb.SubscriptionList_STREAM, 2),
+		newClient(50003, pb.SubscriptionList_STREAM, 1),
+		newClient(50004, pb.SubscriptionList_POLL, 3),
+	} {
+		s.clients[c.String()] = c
+	}
+
+	r := &metrics.Registry{}
+	r.Register(s)
+	var b bytes.Buffer
+	if err := r.WriteText(&b); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	for _, want := range []string{
+		`telemetry_sessions{mode="POLL",target="COUNTERS_DB"} 1`,
+		`telemetry_sessions{mode="STREAM",target="COUNTERS_DB"} 2`,
+		`telemetry_messages_sent_total{mode="POLL",target="COUNTERS_DB"} 3`,
+		`telemetry_messages_sent_total{mode="STREAM",target="COUNTERS_DB"} 8`,
+	} {
+		if !strings.Contains(b.String(), want+"\n") {
+			t.Errorf("got metrics\n%s\nwant %s", b.String(), want)
+		}
+	}
+}
+
 func TestCapabilities(t *testing.T) {
 	//t.Log("Start server")
 	s := createServer(t, 8085)
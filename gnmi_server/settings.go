@@ -0,0 +1,220 @@
+package gnmi
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sdc "github.com/Azure/sonic-telemetry/sonic_data_client"
+	log "github.com/golang/glog"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// SettingsTable is the CONFIG_DB table of the server settings, held
+	// in its SettingsKey entry. The fields are named after the telemetry
+	// flags. The gnmi entry of the table is not used, its fields are
+	// the ones of the SONiC startup script, like client_auth "true".
+	SettingsTable = "TELEMETRY"
+	SettingsKey   = "gnmi_settings"
+)
+
+// settingAliases maps setting names to the flags they set.
+var settingAliases = map[string]string{
+	"log_level": "v",
+}
+
+// Settings are server settings keyed by flag name.
+type Settings map[string]string
+
+func normalizeSettings(in map[string]string) Settings {
+	st := Settings{}
+	for name, value := range in {
+		if alias, ok := settingAliases[name]; ok {
+			name = alias
+		}
+		st[name] = value
+	}
+	return st
+}
+
+// LoadSettingsFile reads the settings of a YAML or JSON file, mapping flag
+// names to values. Lists are joined with commas.
+func LoadSettingsFile(fileName string) (Settings, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var in map[string]interface{}
+	if err := yaml.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("invalid settings file %v: %v", fileName, err)
+	}
+	values := map[string]string{}
+	for name, v := range in {
+		switch v := v.(type) {
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			values[name] = strings.Join(items, ",")
+		case nil:
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+	return normalizeSettings(values), nil
+}
+
+// LoadSettingsDb reads the settings of the SettingsTable of CONFIG_DB.
+func LoadSettingsDb() (Settings, error) {
+	entries, err := sdc.GetDbTable("CONFIG_DB", SettingsTable)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeSettings(entries[SettingsKey]), nil
+}
+
+// SetFlags sets the flags of the settings, except the ones in skip, like
+// the flags set on the command line.
+func (st Settings) SetFlags(fs *flag.FlagSet, skip map[string]bool) error {
+	for _, name := range st.names() {
+		if skip[name] {
+			continue
+		}
+		if err := fs.Set(name, st[name]); err != nil {
+			return fmt.Errorf("invalid setting %v %q: %v", name, st[name], err)
+		}
+	}
+	return nil
+}
+
+func (st Settings) names() []string {
+	names := make([]string, 0, len(st))
+	for name := range st {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runtimeSettings apply the settings that can change while the server is
+// running. The other ones require a restart.
+var runtimeSettings = map[string]func(srv *Server, value string) error{
+	"client_auth": func(srv *Server, value string) error {
+		auth := AuthTypes{"password": false, "cert": false, "jwt": false}
+		if err := auth.Set(value); err != nil {
+			return err
+		}
+		if err := CheckAuthPolicy(auth); err != nil {
+			return err
+		}
+		srv.settingsMu.Lock()
+		defer srv.settingsMu.Unlock()
+		srv.config.UserAuth = auth
+		return nil
+	},
+	"min_sample_interval": func(srv *Server, value string) error {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		sdc.SetMinSampleInterval(interval)
+		return nil
+	},
+	"target_defined": func(srv *Server, value string) error {
+		modes, err := sdc.ParseTargetDefinedModes(value)
+		if err != nil {
+			return err
+		}
+		sdc.SetTargetDefinedModes(modes)
+		return nil
+	},
+	"client_queue_limit": func(srv *Server, value string) error {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		srv.settingsMu.Lock()
+		defer srv.settingsMu.Unlock()
+		srv.config.QueueLimit = limit
+		return nil
+	},
+	"client_queue_policy": func(srv *Server, value string) error {
+		var policy QueuePolicy
+		if err := policy.Set(value); err != nil {
+			return err
+		}
+		srv.settingsMu.Lock()
+		defer srv.settingsMu.Unlock()
+		srv.config.QueuePolicy = policy
+		return nil
+	},
+	"v": func(srv *Server, value string) error {
+		return flag.Set("v", value)
+	},
+}
+
+// UpdateSettings applies the settings changed since old to the running
+// server. Changes of settings requiring a restart are logged and ignored.
+func (srv *Server) UpdateSettings(old, st Settings) {
+	for _, name := range st.names() {
+		value := st[name]
+		if prev, ok := old[name]; ok && prev == value {
+			continue
+		}
+		apply, ok := runtimeSettings[name]
+		if !ok {
+			log.Warningf("Setting %v changed to %q, restart to apply it", name, value)
+			continue
+		}
+		if err := apply(srv, value); err != nil {
+			log.Errorf("Invalid setting %v %q: %v", name, value, err)
+			continue
+		}
+		log.Infof("Setting %v changed to %q", name, value)
+	}
+}
+
+// WatchSettingsDb applies the changes of the CONFIG_DB settings to the
+// running server until stop is closed.
+func (srv *Server) WatchSettingsDb(stop <-chan struct{}) error {
+	current, err := LoadSettingsDb()
+	if err != nil {
+		return err
+	}
+	return sdc.WatchDbTable("CONFIG_DB", SettingsTable, stop, func(key string) {
+		if key != SettingsKey {
+			return
+		}
+		st, err := LoadSettingsDb()
+		if err != nil {
+			log.Errorf("Failed to reload settings: %v", err)
+			return
+		}
+		srv.UpdateSettings(current, st)
+		current = st
+	})
+}
+
+func (srv *Server) userAuth() AuthTypes {
+	srv.settingsMu.RLock()
+	defer srv.settingsMu.RUnlock()
+	return srv.config.UserAuth
+}
+
+func (srv *Server) authzPolicy() *AuthzPolicy {
+	srv.settingsMu.RLock()
+	defer srv.settingsMu.RUnlock()
+	return srv.config.AuthzPolicy
+}
+
+func (srv *Server) queueSettings() (int, QueuePolicy) {
+	srv.settingsMu.RLock()
+	defer srv.settingsMu.RUnlock()
+	return srv.config.QueueLimit, srv.config.QueuePolicy
+}
@@ -0,0 +1,150 @@
+package gnmi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+)
+
+// TLSReloader serves the server certificate and the client CA pool loaded
+// from files, reloading them when the files change. New handshakes use the
+// latest files successfully loaded.
+type TLSReloader struct {
+	base     *tls.Config
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu      sync.RWMutex
+	config  *tls.Config
+	modTime map[string]time.Time
+}
+
+// NewTLSReloader loads the certificate, key and CA files into a copy of
+// base. certFile and keyFile may be empty to keep the certificates of base,
+// caFile to keep its client CAs.
+func NewTLSReloader(base *tls.Config, certFile, keyFile, caFile string) (*TLSReloader, error) {
+	r := &TLSReloader{
+		base:     base.Clone(),
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *TLSReloader) files() []string {
+	var files []string
+	for _, f := range []string{r.certFile, r.keyFile, r.caFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func (r *TLSReloader) load() error {
+	modTime := map[string]time.Time{}
+	for _, f := range r.files() {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		modTime[f] = fi.ModTime()
+	}
+
+	config := r.base.Clone()
+	// Configurations returned by GetConfigForClient replace the one gRPC
+	// added its ALPN protocol to.
+	config.NextProtos = append(config.NextProtos, "h2")
+	// Revoked client certificates fail the handshake. The RPCs of the
+	// connections established before a revocation are rejected by the
+	// cert authentication.
+	config.VerifyPeerCertificate = verifyClientCrls(r.base.VerifyPeerCertificate)
+	if r.certFile != "" {
+		certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("could not load server key pair: %v", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	if r.caFile != "" {
+		ca, err := ioutil.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("could not read CA certificate: %v", err)
+		}
+		certPool := x509.NewCertPool()
+		if ok := certPool.AppendCertsFromPEM(ca); !ok {
+			return fmt.Errorf("failed to append CA certificate")
+		}
+		config.ClientCAs = certPool
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.config = config
+	r.modTime = modTime
+	return nil
+}
+
+// changed tells whether a file was modified since it was loaded.
+func (r *TLSReloader) changed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, f := range r.files() {
+		fi, err := os.Stat(f)
+		if err != nil || !fi.ModTime().Equal(r.modTime[f]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reload loads the files again if any of them changed. The files in use are
+// kept if they fail to load.
+func (r *TLSReloader) Reload() error {
+	if !r.changed() {
+		return nil
+	}
+	if err := r.load(); err != nil {
+		log.Errorf("Failed to reload TLS certificates: %v", err)
+		return err
+	}
+	log.Infof("Reloaded TLS certificates from %v", r.files())
+	return nil
+}
+
+// Watch checks the files for changes every interval until stop is closed.
+func (r *TLSReloader) Watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.Reload()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Config returns the TLS configuration of the server, which takes the
+// latest certificate and CA pool loaded for each handshake.
+func (r *TLSReloader) Config() *tls.Config {
+	config := r.base.Clone()
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		return r.config, nil
+	}
+	return config
+}
@@ -5,6 +5,7 @@ import (
 	gnoi_system_pb "github.com/openconfig/gnoi/system"
 	spb "github.com/Azure/sonic-telemetry/proto/gnoi"
 	spb_jwt "github.com/Azure/sonic-telemetry/proto/gnoi/jwt"
+	spb_admin "github.com/Azure/sonic-telemetry/proto/gnoi/admin"
 	"context"
 	"os"
 	"os/signal"
@@ -77,9 +78,27 @@ func main() {
 		case "refresh":
 			sc := spb_jwt.NewSonicJwtServiceClient(conn)
 			refresh(sc, ctx)
+		case "publicKeys":
+			sc := spb_jwt.NewSonicJwtServiceClient(conn)
+			publicKeys(sc, ctx)
+		case "revoke":
+			sc := spb_jwt.NewSonicJwtServiceClient(conn)
+			revoke(sc, ctx)
 		case "clearNeighbors":
 			sc := spb.NewSonicServiceClient(conn)
 			clearNeighbors(sc, ctx)
+		case "listSessions":
+			sc := spb_admin.NewSonicAdminServiceClient(conn)
+			listSessions(sc, ctx)
+		case "getSession":
+			sc := spb_admin.NewSonicAdminServiceClient(conn)
+			getSession(sc, ctx)
+		case "terminateSession":
+			sc := spb_admin.NewSonicAdminServiceClient(conn)
+			terminateSession(sc, ctx)
+		case "listLockouts":
+			sc := spb_admin.NewSonicAdminServiceClient(conn)
+			listLockouts(sc, ctx)
 		default:
 			panic("Invalid RPC Name")
 		}
@@ -239,6 +258,94 @@ func refresh(sc spb_jwt.SonicJwtServiceClient, ctx context.Context) {
 	fmt.Println(string(respstr))
 }
 
+func publicKeys(sc spb_jwt.SonicJwtServiceClient, ctx context.Context) {
+	fmt.Println("Sonic PublicKeys")
+	resp, err := sc.PublicKeys(ctx, &spb_jwt.PublicKeysRequest{})
+	if err != nil {
+		panic(err.Error())
+	}
+	fmt.Println(resp.Jwks)
+}
+
+func revoke(sc spb_jwt.SonicJwtServiceClient, ctx context.Context) {
+	fmt.Println("Sonic Revoke")
+	ctx = setUserCreds(ctx)
+	req := &spb_jwt.RevokeRequest{}
+
+	json.Unmarshal([]byte(*args), req)
+
+	_, err := sc.Revoke(ctx, req)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func listSessions(sc spb_admin.SonicAdminServiceClient, ctx context.Context) {
+	fmt.Println("Sonic ListSessions")
+	ctx = setUserCreds(ctx)
+	req := &spb_admin.ListSessionsRequest{}
+
+	resp, err := sc.ListSessions(ctx, req)
+	if err != nil {
+		panic(err.Error())
+	}
+	respstr, err := json.Marshal(resp)
+	if err != nil {
+		panic(err.Error())
+	}
+	fmt.Println(string(respstr))
+}
+
+func getSession(sc spb_admin.SonicAdminServiceClient, ctx context.Context) {
+	fmt.Println("Sonic GetSession")
+	ctx = setUserCreds(ctx)
+	req := &spb_admin.GetSessionRequest{}
+	json.Unmarshal([]byte(*args), req)
+
+	resp, err := sc.GetSession(ctx, req)
+	if err != nil {
+		panic(err.Error())
+	}
+	respstr, err := json.Marshal(resp)
+	if err != nil {
+		panic(err.Error())
+	}
+	fmt.Println(string(respstr))
+}
+
+func terminateSession(sc spb_admin.SonicAdminServiceClient, ctx context.Context) {
+	fmt.Println("Sonic TerminateSession")
+	ctx = setUserCreds(ctx)
+	req := &spb_admin.TerminateSessionRequest{}
+	json.Unmarshal([]byte(*args), req)
+
+	resp, err := sc.TerminateSession(ctx, req)
+	if err != nil {
+		panic(err.Error())
+	}
+	respstr, err := json.Marshal(resp)
+	if err != nil {
+		panic(err.Error())
+	}
+	fmt.Println(string(respstr))
+}
+
+func listLockouts(sc spb_admin.SonicAdminServiceClient, ctx context.Context) {
+	fmt.Println("Sonic ListLockouts")
+	ctx = setUserCreds(ctx)
+	req := &spb_admin.ListLockoutsRequest{}
+
+	resp, err := sc.ListLockouts(ctx, req)
+	if err != nil {
+		panic(err.Error())
+	}
+	respstr, err := json.Marshal(resp)
+	if err != nil {
+		panic(err.Error())
+	}
+	fmt.Println(string(respstr))
+}
+
 func clearNeighbors(sc spb.SonicServiceClient, ctx context.Context) {
     fmt.Println("Sonic ClearNeighbors")
     ctx = setUserCreds(ctx)
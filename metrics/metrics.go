@@ -0,0 +1,272 @@
+// Package metrics exposes the internal metrics of the telemetry services in
+// the Prometheus text format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Collector writes a set of metrics when they are scraped.
+type Collector interface {
+	Collect(w *Writer)
+}
+
+// Registry holds the collectors exposed together.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+// DefaultRegistry holds the metrics created by NewCounter and NewHistogram.
+var DefaultRegistry = &Registry{}
+
+// Register adds a collector to the default registry.
+func Register(c Collector) {
+	DefaultRegistry.Register(c)
+}
+
+// Unregister removes a collector from the default registry.
+func Unregister(c Collector) {
+	DefaultRegistry.Unregister(c)
+}
+
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+func (r *Registry) Unregister(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, rc := range r.collectors {
+		if rc == c {
+			r.collectors = append(r.collectors[:i], r.collectors[i+1:]...)
+			return
+		}
+	}
+}
+
+// WriteText writes the metrics of all the collectors in the Prometheus text
+// format.
+func (r *Registry) WriteText(out io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]Collector{}, r.collectors...)
+	r.mu.Unlock()
+
+	w := &Writer{w: bufio.NewWriter(out), families: map[string]bool{}}
+	for _, c := range collectors {
+		c.Collect(w)
+	}
+	return w.w.Flush()
+}
+
+// Writer writes metric families in the Prometheus text format.
+type Writer struct {
+	w        *bufio.Writer
+	families map[string]bool
+}
+
+// Family starts a metric family of type typ, which is counter, gauge or
+// histogram. The samples of a family must be written right after it.
+func (w *Writer) Family(name, help, typ string) {
+	if w.families[name] {
+		return
+	}
+	w.families[name] = true
+	fmt.Fprintf(w.w, "# HELP %s %s\n", name, strings.NewReplacer("\\", `\\`, "\n", `\n`).Replace(help))
+	fmt.Fprintf(w.w, "# TYPE %s %s\n", name, typ)
+}
+
+// Sample writes a sample of a metric. labels are pairs of label names and
+// values.
+func (w *Writer) Sample(name string, value float64, labels ...string) {
+	w.w.WriteString(name)
+	if len(labels) > 1 {
+		w.w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.w.WriteByte(',')
+			}
+			fmt.Fprintf(w.w, "%s=\"%s\"", labels[i], escapeLabel(labels[i+1]))
+		}
+		w.w.WriteByte('}')
+	}
+	w.w.WriteByte(' ')
+	w.w.WriteString(formatValue(value))
+	w.w.WriteByte('\n')
+}
+
+func escapeLabel(v string) string {
+	return strings.NewReplacer("\\", `\\`, "\"", `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// labelPairs interleaves label names and values.
+func labelPairs(names, values []string) []string {
+	pairs := make([]string, 0, 2*len(names))
+	for i, name := range names {
+		v := ""
+		if i < len(values) {
+			v = values[i]
+		}
+		pairs = append(pairs, name, v)
+	}
+	return pairs
+}
+
+func labelKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// Counter is a monotonic counter, with one value per set of label values.
+type Counter struct {
+	name   string
+	help   string
+	labels []string
+	mu     sync.Mutex
+	values map[string]*counterValue
+}
+
+type counterValue struct {
+	labels []string
+	value  float64
+}
+
+// NewCounter returns a counter registered in the default registry.
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{name: name, help: help, labels: labels, values: map[string]*counterValue{}}
+	Register(c)
+	return c
+}
+
+// Inc adds 1 to the counter of the label values.
+func (c *Counter) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+// Add adds v to the counter of the label values.
+func (c *Counter) Add(v float64, labels ...string) {
+	key := labelKey(labels)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cv, ok := c.values[key]
+	if !ok {
+		cv = &counterValue{labels: labelPairs(c.labels, labels)}
+		c.values[key] = cv
+	}
+	cv.value += v
+}
+
+func (c *Counter) Collect(w *Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w.Family(c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.values) {
+		cv := c.values[key]
+		w.Sample(c.name, cv.value, cv.labels...)
+	}
+}
+
+// DefBuckets are the default latency buckets, in seconds.
+var DefBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+// Histogram counts observations in buckets, with one set of buckets per set
+// of label values.
+type Histogram struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labels []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram returns a histogram registered in the default registry.
+// buckets are the sorted upper bounds of the buckets.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{name: name, help: help, labels: labels, buckets: buckets, values: map[string]*histogramValue{}}
+	Register(h)
+	return h
+}
+
+// Observe adds an observation to the histogram of the label values.
+func (h *Histogram) Observe(v float64, labels ...string) {
+	key := labelKey(labels)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hv, ok := h.values[key]
+	if !ok {
+		hv = &histogramValue{labels: labelPairs(h.labels, labels), counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hv
+	}
+	for i, le := range h.buckets {
+		if v <= le {
+			hv.counts[i]++
+		}
+	}
+	hv.count++
+	hv.sum += v
+}
+
+// ObserveSince observes the seconds elapsed since start.
+func (h *Histogram) ObserveSince(start time.Time, labels ...string) {
+	h.Observe(time.Since(start).Seconds(), labels...)
+}
+
+func (h *Histogram) Collect(w *Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	w.Family(h.name, h.help, "histogram")
+	for _, key := range sortedKeys(h.values) {
+		hv := h.values[key]
+		for i, le := range h.buckets {
+			w.Sample(h.name+"_bucket", float64(hv.counts[i]), append(hv.labels, "le", formatValue(le))...)
+		}
+		w.Sample(h.name+"_bucket", float64(hv.count), append(hv.labels, "le", "+Inf")...)
+		w.Sample(h.name+"_sum", hv.sum, hv.labels...)
+		w.Sample(h.name+"_count", float64(hv.count), hv.labels...)
+	}
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]*counterValue:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*histogramValue:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
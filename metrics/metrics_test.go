@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type sessionCollector struct {
+	sessions map[string]int
+}
+
+func (c *sessionCollector) Collect(w *Writer) {
+	w.Family("test_sessions", "Active sessions.", "gauge")
+	for _, mode := range []string{"poll", "stream"} {
+		w.Sample("test_sessions", float64(c.sessions[mode]), "mode", mode)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	r := &Registry{}
+	c := &Counter{name: "test_requests_total", help: "Requests.", labels: []string{"op", "code"}, values: map[string]*counterValue{}}
+	h := &Histogram{name: "test_latency_seconds", help: "Latency.", labels: []string{"op"}, buckets: []float64{.01, .1}, values: map[string]*histogramValue{}}
+	s := &sessionCollector{sessions: map[string]int{"stream": 2}}
+	r.Register(c)
+	r.Register(h)
+	r.Register(s)
+
+	c.Inc("set", "OK")
+	c.Add(2, "get", "OK")
+	c.Inc("get", "NOT_FOUND")
+	h.Observe(0.0078125, "get")
+	h.Observe(0.0625, "get")
+	h.Observe(1, "get")
+
+	var b bytes.Buffer
+	if err := r.WriteText(&b); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	want := `# HELP test_requests_total Requests.
+# TYPE test_requests_total counter
+test_requests_total{op="get",code="NOT_FOUND"} 1
+test_requests_total{op="get",code="OK"} 2
+test_requests_total{op="set",code="OK"} 1
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{op="get",le="0.01"} 1
+test_latency_seconds_bucket{op="get",le="0.1"} 2
+test_latency_seconds_bucket{op="get",le="+Inf"} 3
+test_latency_seconds_sum{op="get"} 1.0703125
+test_latency_seconds_count{op="get"} 3
+# HELP test_sessions Active sessions.
+# TYPE test_sessions gauge
+test_sessions{mode="poll"} 0
+test_sessions{mode="stream"} 2
+`
+	if got := b.String(); got != want {
+		t.Errorf("got metrics\n%s\nwant\n%s", got, want)
+	}
+
+	r.Unregister(s)
+	b.Reset()
+	r.WriteText(&b)
+	if strings.Contains(b.String(), "test_sessions") {
+		t.Errorf("got metrics of an unregistered collector:\n%s", b.String())
+	}
+}
+
+func TestServeUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewCounter("test_served_total", "Served.")
+	defer Unregister(c)
+	c.Inc()
+
+	path := filepath.Join(dir, "metrics.sock")
+	lis, err := Listen("unix:" + path)
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer lis.Close()
+	go Serve(lis)
+
+	client := &http.Client{Transport: &http.Transport{
+		Dial: func(_, _ string) (net.Conn, error) {
+			return net.Dial("unix", path)
+		},
+	}}
+	resp, err := client.Get("http://localhost/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "test_served_total 1\n") {
+		t.Errorf("got metrics\n%s\nwant test_served_total 1", body)
+	}
+}
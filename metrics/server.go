@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	log "github.com/golang/glog"
+)
+
+// Handler returns the HTTP handler exposing the metrics of the default
+// registry.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := DefaultRegistry.WriteText(w); err != nil {
+			log.V(2).Infof("Failed to write metrics: %v", err)
+		}
+	})
+}
+
+// Listen opens the listener of the metrics endpoint. addr is either a TCP
+// address like ":9100" or a unix socket path prefixed with "unix:".
+func Listen(addr string) (net.Listener, error) {
+	if strings.HasPrefix(addr, "unix:") {
+		path := strings.TrimPrefix(addr, "unix:")
+		os.Remove(path)
+		return net.Listen("unix", path)
+	}
+	return net.Listen("tcp", addr)
+}
+
+// Serve serves the metrics of the default registry on /metrics of the
+// listener, blocking until it fails.
+func Serve(lis net.Listener) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	return http.Serve(lis, mux)
+}
@@ -10,15 +10,19 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/golang/glog"
 
+	"github.com/Azure/sonic-telemetry/common_utils"
 	spb "github.com/Azure/sonic-telemetry/proto"
 	sdcfg "github.com/Azure/sonic-telemetry/sonic_db_config"
 	"github.com/Workiva/go-datastructures/queue"
 	"github.com/go-redis/redis"
 	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -68,6 +72,22 @@ var Target2RedisDb = make(map[string]map[string]*redis.Client)
 // Any non-zero value that less than this threshold is considered invalid argument.
 var MinSampleInterval = time.Second
 
+// Protects MinSampleInterval changed while subscriptions are running
+var minSampleIntervalMu sync.RWMutex
+
+// SetMinSampleInterval changes MinSampleInterval for new subscriptions.
+func SetMinSampleInterval(interval time.Duration) {
+	minSampleIntervalMu.Lock()
+	defer minSampleIntervalMu.Unlock()
+	MinSampleInterval = interval
+}
+
+func minSampleInterval() time.Duration {
+	minSampleIntervalMu.RLock()
+	defer minSampleIntervalMu.RUnlock()
+	return MinSampleInterval
+}
+
 // IntervalTicker is a factory method to implement interval ticking.
 // Exposed for UT purposes.
 var IntervalTicker = func(interval time.Duration) <-chan time.Time {
@@ -93,6 +113,13 @@ type tablePath struct {
 
 type Value struct {
 	*spb.Value
+	// Updates, if not nil, are sent instead of Path and Val. DbClient
+	// builds them for PROTO encoding, without going through JSON.
+	Updates []*gnmipb.Update
+	// Keys are the table keys of the data of the value, for the values of
+	// tables and wildcard keys. The ON_CHANGE updates of such a path only
+	// hold the keys that changed.
+	Keys []string
 }
 
 // Implement Compare method for priority queue
@@ -107,21 +134,26 @@ func (val Value) Compare(other queue.Item) int {
 }
 
 type DbClient struct {
-	prefix  *gnmipb.Path
-	pathG2S map[*gnmipb.Path][]tablePath
-	q       *queue.PriorityQueue
-	channel chan struct{}
+	prefix   *gnmipb.Path
+	pathG2S  map[*gnmipb.Path][]tablePath
+	q        *queue.PriorityQueue
+	channel  chan struct{}
+	encoding gnmipb.Encoding
 
 	synced sync.WaitGroup  // Control when to send gNMI sync_response
 	w      *sync.WaitGroup // wait for all sub go routines to finish
 	mu     sync.RWMutex    // Mutex for data protection among routines for DbClient
 
+	// Keyspace notifications of the field subscriptions, shared by DB
+	keyspaceMu sync.Mutex
+	keyspaces  map[*redis.Client]*dbKeyspaceMux
+
 	sendMsg int64
 	recvMsg int64
 	errors  int64
 }
 
-func NewDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path) (Client, error) {
+func NewDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path, encoding gnmipb.Encoding) (Client, error) {
 	var client DbClient
 	var err error
 
@@ -131,6 +163,7 @@ func NewDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path) (Client, error) {
 	}
 
 	client.prefix = prefix
+	client.encoding = encoding
 	client.pathG2S = make(map[*gnmipb.Path][]tablePath)
 	err = populateAllDbtablePath(prefix, paths, &client.pathG2S)
 
@@ -141,6 +174,59 @@ func NewDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path) (Client, error) {
 	}
 }
 
+// valuePath returns the prefix and path of the updates of the data read for
+// gnmiPath with PROTO encoding. Leaves are sent with their own path, so the
+// path of data read for a wildcard key is cut before the wildcard: the data
+// is keyed by the matching table keys.
+func (c *DbClient) valuePath(gnmiPath *gnmipb.Path) (*gnmipb.Path, *gnmipb.Path) {
+	elems := gnmiFullPath(c.prefix, gnmiPath).GetElem()
+	for i, elem := range elems {
+		if strings.Contains(elem.GetName(), "*") {
+			prefix := &gnmipb.Path{Target: c.prefix.GetTarget(), Origin: c.prefix.GetOrigin()}
+			return prefix, &gnmipb.Path{Elem: elems[:i]}
+		}
+	}
+	return c.prefix, gnmiPath
+}
+
+// newValue returns the value of the data read for gnmiPath, either the
+// value of a field or a map of keys and fields. The map is sent as
+// JSON_IETF, or with PROTO encoding as one update per field, with a typed
+// scalar value built from the redis string.
+func (c *DbClient) newValue(gnmiPath *gnmipb.Path, data interface{}) (Value, error) {
+	spbv := &spb.Value{
+		Prefix:    c.prefix,
+		Path:      gnmiPath,
+		Timestamp: time.Now().UnixNano(),
+	}
+	msi, isMsi := data.(map[string]interface{})
+	var keys []string
+	for key := range msi {
+		keys = append(keys, key)
+	}
+	switch {
+	case isMsi && c.encoding == gnmipb.Encoding_PROTO:
+		spbv.Prefix, spbv.Path = c.valuePath(gnmiPath)
+		updates := msi2Updates(msi, spbv.Path.GetElem(), make([]*gnmipb.Update, 0, len(msi)))
+		return Value{Value: spbv, Updates: updates, Keys: keys}, nil
+	case isMsi:
+		val, err := msi2TypedValue(msi)
+		if err != nil {
+			return Value{}, err
+		}
+		spbv.Val = val
+		return Value{Value: spbv, Keys: keys}, nil
+	case c.encoding == gnmipb.Encoding_PROTO:
+		spbv.Val = TypedScalar(data.(string))
+	default:
+		spbv.Val = &gnmipb.TypedValue{
+			Value: &gnmipb.TypedValue_StringVal{
+				StringVal: data.(string),
+			}}
+	}
+	return Value{Value: spbv}, nil
+}
+
 // String returns the target the client is querying.
 func (c *DbClient) String() string {
 	// TODO: print gnmiPaths of this DbClient
@@ -162,7 +248,7 @@ func (c *DbClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *sync
 		for gnmiPath := range c.pathG2S {
 			c.w.Add(1)
 			c.synced.Add(1)
-			go streamOnChangeSubscription(c, gnmiPath)
+			go streamOnChangeSubscription(c, gnmiPath, streamOptions{})
 		}
 	} else {
 		log.V(2).Infof("Stream subscription request received, mode: %v, subscription count: %v",
@@ -171,16 +257,26 @@ func (c *DbClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *sync
 
 		for _, sub := range subscribe.GetSubscription() {
 			log.V(2).Infof("Sub mode: %v, path: %v", sub.GetMode(), sub.GetPath())
+			if sub.GetMode() == gnmipb.SubscriptionMode_TARGET_DEFINED {
+				tblPath := c.pathG2S[sub.GetPath()][0]
+				sub = resolveTargetDefined(sub, tblPath.dbName, tblPath.tableName)
+				log.V(2).Infof("TARGET_DEFINED resolved to mode: %v, sample interval: %v", sub.GetMode(), time.Duration(sub.GetSampleInterval()))
+			}
 			subMode := sub.GetMode()
 
+			opts, err := subscriptionOptions(sub)
+			if err != nil {
+				enqueueFatalMsg(c, err.Error())
+				return
+			}
 			if subMode == gnmipb.SubscriptionMode_SAMPLE {
 				c.w.Add(1)      // wait group to indicate the streaming session is complete.
 				c.synced.Add(1) // wait group to indicate whether sync_response is sent.
-				go streamSampleSubscription(c, sub, subscribe.GetUpdatesOnly())
+				go streamSampleSubscription(c, sub, subscribe.GetUpdatesOnly(), opts)
 			} else if subMode == gnmipb.SubscriptionMode_ON_CHANGE {
 				c.w.Add(1)
 				c.synced.Add(1)
-				go streamOnChangeSubscription(c, sub.GetPath())
+				go streamOnChangeSubscription(c, sub.GetPath(), opts)
 			} else {
 				enqueueFatalMsg(c, fmt.Sprintf("unsupported subscription mode, %v", subMode))
 				return
@@ -192,8 +288,8 @@ func (c *DbClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *sync
 	// in the SubscriptionList has been transmitted at least once
 	c.synced.Wait()
 	// Inject sync message
-	c.q.Put(Value{
-		&spb.Value{
+	c.put(Value{
+		Value: &spb.Value{
 			Timestamp:    time.Now().UnixNano(),
 			SyncResponse: true,
 		},
@@ -205,24 +301,24 @@ func (c *DbClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *sync
 }
 
 // streamOnChangeSubscription implements Subscription "ON_CHANGE STREAM" mode
-func streamOnChangeSubscription(c *DbClient, gnmiPath *gnmipb.Path) {
+func streamOnChangeSubscription(c *DbClient, gnmiPath *gnmipb.Path, opts streamOptions) {
 	tblPaths := c.pathG2S[gnmiPath]
 	log.V(2).Infof("streamOnChangeSubscription gnmiPath: %v", gnmiPath)
 
 	if tblPaths[0].field != "" {
 		if len(tblPaths) > 1 {
-			go dbFieldMultiSubscribe(c, gnmiPath, true, time.Millisecond*200, false)
+			go dbFieldMultiSubscribe(c, gnmiPath, true, 0, false, opts)
 		} else {
-			go dbFieldSubscribe(c, gnmiPath, true, time.Millisecond*200)
+			go dbFieldSubscribe(c, gnmiPath, true, 0, opts)
 		}
 	} else {
 		// sample interval and update only parameters are not applicable
-		go dbTableKeySubscribe(c, gnmiPath, 0, true)
+		go dbTableKeySubscribe(c, gnmiPath, 0, true, opts)
 	}
 }
 
 // streamSampleSubscription implements Subscription "SAMPLE STREAM" mode
-func streamSampleSubscription(c *DbClient, sub *gnmipb.Subscription, updateOnly bool) {
+func streamSampleSubscription(c *DbClient, sub *gnmipb.Subscription, updateOnly bool, opts streamOptions) {
 	samplingInterval, err := validateSampleInterval(sub)
 	if err != nil {
 		enqueueFatalMsg(c, err.Error())
@@ -236,13 +332,45 @@ func streamSampleSubscription(c *DbClient, sub *gnmipb.Subscription, updateOnly
 	log.V(2).Infof("streamSampleSubscription gnmiPath: %v", gnmiPath)
 	if tblPaths[0].field != "" {
 		if len(tblPaths) > 1 {
-			dbFieldMultiSubscribe(c, gnmiPath, false, samplingInterval, updateOnly)
+			dbFieldMultiSubscribe(c, gnmiPath, false, samplingInterval, updateOnly, opts)
 		} else {
-			dbFieldSubscribe(c, gnmiPath, false, samplingInterval)
+			dbFieldSubscribe(c, gnmiPath, false, samplingInterval, opts)
 		}
 	} else {
-		dbTableKeySubscribe(c, gnmiPath, samplingInterval, updateOnly)
+		dbTableKeySubscribe(c, gnmiPath, samplingInterval, updateOnly, opts)
+	}
+}
+
+// streamOptions are the heartbeat_interval and suppress_redundant options of
+// a STREAM subscription.
+type streamOptions struct {
+	// Interval at which unchanged data is sent again, 0 for never. It
+	// applies to ON_CHANGE subscriptions, and to SAMPLE subscriptions
+	// suppressing redundant data.
+	heartbeat time.Duration
+	// SAMPLE data is sent only when it changed, or at heartbeat
+	suppressRedundant bool
+}
+
+// subscriptionOptions returns the stream options of a subscription.
+func subscriptionOptions(sub *gnmipb.Subscription) (streamOptions, error) {
+	opts := streamOptions{
+		heartbeat:         time.Duration(sub.GetHeartbeatInterval()),
+		suppressRedundant: sub.GetSuppressRedundant(),
+	}
+	if opts.heartbeat != 0 && opts.heartbeat < minSampleInterval() {
+		return opts, fmt.Errorf("invalid heartbeat interval: %v. It cannot be less than %v", opts.heartbeat, minSampleInterval())
 	}
+	return opts, nil
+}
+
+// heartbeatTicks returns the number of ticks of interval after which
+// unchanged data is sent again, 0 for never.
+func (opts streamOptions) heartbeatTicks(onChange bool, interval time.Duration) int {
+	if opts.heartbeat == 0 || (!onChange && !opts.suppressRedundant) {
+		return 0
+	}
+	return int((opts.heartbeat + interval - 1) / interval)
 }
 
 func (c *DbClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, w *sync.WaitGroup, subscribe *gnmipb.SubscriptionList) {
@@ -259,25 +387,22 @@ func (c *DbClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, w *sync.W
 		}
 		t1 := time.Now()
 		for gnmiPath, tblPaths := range c.pathG2S {
-			val, err := tableData2TypedValue(tblPaths, nil)
+			data, err := tableData(tblPaths, nil)
 			if err != nil {
 				return
 			}
 
-			spbv := &spb.Value{
-				Prefix:       c.prefix,
-				Path:         gnmiPath,
-				Timestamp:    time.Now().UnixNano(),
-				SyncResponse: false,
-				Val:          val,
+			val, err := c.newValue(gnmiPath, data)
+			if err != nil {
+				return
 			}
 
-			c.q.Put(Value{spbv})
-			log.V(6).Infof("Added spbv #%v", spbv)
+			c.put(val)
+			log.V(6).Infof("Added spbv #%v", val.Value)
 		}
 
-		c.q.Put(Value{
-			&spb.Value{
+		c.put(Value{
+			Value: &spb.Value{
 				Timestamp:    time.Now().UnixNano(),
 				SyncResponse: true,
 			},
@@ -285,8 +410,46 @@ func (c *DbClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, w *sync.W
 		log.V(4).Infof("Sync done, poll time taken: %v ms", int64(time.Since(t1)/time.Millisecond))
 	}
 }
+// OnceRun implements Subscription "ONCE" mode. It sends the current value of
+// every subscribed path followed by a sync_response, and then exits.
 func (c *DbClient) OnceRun(q *queue.PriorityQueue, once chan struct{}, w *sync.WaitGroup, subscribe *gnmipb.SubscriptionList) {
-	return
+	c.w = w
+	defer c.w.Done()
+	c.q = q
+	c.channel = once
+
+	_, more := <-c.channel
+	if !more {
+		log.V(1).Infof("%v once channel closed, exiting onceDb routine", c)
+		return
+	}
+	t1 := time.Now()
+	for gnmiPath, tblPaths := range c.pathG2S {
+		data, err := tableData(tblPaths, nil)
+		if err != nil {
+			enqueueFatalMsg(c, err.Error())
+			return
+		}
+
+		if !subscribe.GetUpdatesOnly() {
+			val, err := c.newValue(gnmiPath, data)
+			if err != nil {
+				enqueueFatalMsg(c, err.Error())
+				return
+			}
+
+			c.put(val)
+			log.V(6).Infof("Added spbv #%v", val.Value)
+		}
+	}
+
+	c.put(Value{
+		Value: &spb.Value{
+			Timestamp:    time.Now().UnixNano(),
+			SyncResponse: true,
+		},
+	})
+	log.V(4).Infof("Sync done, once time taken: %v ms", int64(time.Since(t1)/time.Millisecond))
 }
 func (c *DbClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 	// wait sync for Get, not used for now
@@ -295,17 +458,29 @@ func (c *DbClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 	var values []*spb.Value
 	ts := time.Now()
 	for gnmiPath, tblPaths := range c.pathG2S {
-		val, err := tableData2TypedValue(tblPaths, nil)
+		data, err := tableData(tblPaths, nil)
 		if err != nil {
 			return nil, err
 		}
 
-		values = append(values, &spb.Value{
-			Prefix:    c.prefix,
-			Path:      gnmiPath,
-			Timestamp: ts.UnixNano(),
-			Val:       val,
-		})
+		val, err := c.newValue(gnmiPath, data)
+		if err != nil {
+			return nil, err
+		}
+		if val.Updates == nil {
+			val.Timestamp = ts.UnixNano()
+			values = append(values, val.Value)
+			continue
+		}
+		// Values returned by Get hold a single update each.
+		for _, u := range val.Updates {
+			values = append(values, &spb.Value{
+				Prefix:    val.GetPrefix(),
+				Path:      u.GetPath(),
+				Timestamp: ts.UnixNano(),
+				Val:       u.GetVal(),
+			})
+		}
 	}
 	log.V(6).Infof("Getting #%v", values)
 	log.V(4).Infof("Get done, total time taken: %v ms", int64(time.Since(ts)/time.Millisecond))
@@ -318,8 +493,8 @@ func (c *DbClient) Close() error {
 }
 
 // Convert from SONiC Value to its corresponding gNMI proto stream
-// response type.
-func ValToResp(val Value) (*gnmipb.SubscribeResponse, error) {
+// response type, in the requested encoding.
+func ValToResp(val Value, encoding gnmipb.Encoding) (*gnmipb.SubscribeResponse, error) {
 	switch val.GetSyncResponse() {
 	case true:
 		return &gnmipb.SubscribeResponse{
@@ -333,17 +508,20 @@ func ValToResp(val Value) (*gnmipb.SubscribeResponse, error) {
 			return nil, fmt.Errorf("%s", fatal)
 		}
 
+		updates := val.Updates
+		if updates == nil {
+			var err error
+			if updates, err = ValToUpdates(val.Value, encoding); err != nil {
+				return nil, err
+			}
+		}
+
 		return &gnmipb.SubscribeResponse{
 			Response: &gnmipb.SubscribeResponse_Update{
 				Update: &gnmipb.Notification{
 					Timestamp: val.GetTimestamp(),
 					Prefix:    val.GetPrefix(),
-					Update: []*gnmipb.Update{
-						{
-							Path: val.GetPath(),
-							Val:  val.GetVal(),
-						},
-					},
+					Update:    updates,
 				},
 			},
 		}, nil
@@ -375,6 +553,110 @@ func GetRedisClientsForDb(target string) map[string]*redis.Client {
 	return redis_client_map
 }
 
+// GetDbTable returns the entries of a table of a SONiC DB in the default
+// namespace, keyed by table key.
+func GetDbTable(target string, table string) (map[string]map[string]string, error) {
+	ns := sdcfg.GetDbDefaultNamespace()
+	redisDb, ok := Target2RedisDb[ns][target]
+	if !ok {
+		return nil, fmt.Errorf("%v not a valid redis db target", target)
+	}
+	separator, err := GetTableKeySeparator(target, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	dbkeys, err := dbScanKeys(redisDb, redisPatternEscape(table+separator)+"*")
+	if err != nil {
+		return nil, fmt.Errorf("redis Scan failed for %v table %v: %v", target, table, err)
+	}
+	entries := make(map[string]map[string]string)
+	for _, dbkey := range dbkeys {
+		fv, err := redisDb.HGetAll(dbkey).Result()
+		if err != nil {
+			return nil, fmt.Errorf("redis HGetAll failed for %v key %v: %v", target, dbkey, err)
+		}
+		entries[dbkey[len(table)+len(separator):]] = fv
+	}
+	return entries, nil
+}
+
+// SetDbTableEntry sets the fields of an entry of a table of a SONiC DB in
+// the default namespace. Unless expireAt is zero, redis deletes the entry
+// at that time.
+func SetDbTableEntry(target string, table string, key string, fields map[string]string, expireAt time.Time) error {
+	ns := sdcfg.GetDbDefaultNamespace()
+	redisDb, ok := Target2RedisDb[ns][target]
+	if !ok {
+		return fmt.Errorf("%v not a valid redis db target", target)
+	}
+	separator, err := GetTableKeySeparator(target, ns)
+	if err != nil {
+		return err
+	}
+
+	dbkey := table + separator + key
+	fv := make(map[string]interface{}, len(fields))
+	for f, v := range fields {
+		fv[f] = v
+	}
+	_, err = redisDb.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.HMSet(dbkey, fv)
+		if !expireAt.IsZero() {
+			pipe.ExpireAt(dbkey, expireAt)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis HMSet failed for %v key %v: %v", target, dbkey, err)
+	}
+	return nil
+}
+
+// WatchDbTable calls changed with the key of each entry of a table of a
+// SONiC DB in the default namespace that is modified, until stop is closed.
+// It relies on the keyspace notifications of redis.
+func WatchDbTable(target string, table string, stop <-chan struct{}, changed func(key string)) error {
+	ns := sdcfg.GetDbDefaultNamespace()
+	redisDb, ok := Target2RedisDb[ns][target]
+	if !ok {
+		return fmt.Errorf("%v not a valid redis db target", target)
+	}
+	separator, err := GetTableKeySeparator(target, ns)
+	if err != nil {
+		return err
+	}
+
+	pattern := "__keyspace@" + strconv.Itoa(sdcfg.GetDbId(target, ns)) + "__:" + table + separator
+	prefixLen := len(pattern)
+	pattern += "*"
+	pubsub := redisDb.PSubscribe(pattern)
+	defer pubsub.Close()
+	if _, err := pubsub.ReceiveTimeout(time.Second); err != nil {
+		return fmt.Errorf("psubscribe to %s failed: %v", pattern, err)
+	}
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		msgi, err := pubsub.ReceiveTimeout(time.Second)
+		if err != nil {
+			if neterr, ok := err.(net.Error); ok && neterr.Timeout() {
+				continue
+			}
+			log.V(2).Infof("pubsub.ReceiveTimeout err %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		if msg, ok := msgi.(*redis.Message); ok {
+			changed(msg.Channel[prefixLen:])
+		}
+	}
+}
+
 // This function get target present in GNMI Request and
 // returns: 1. DbName (string) 2. Is DbName valid (bool)
 //          3. DbNamespace (string) 4. Is DbNamespace present in Target (bool)
@@ -402,6 +684,25 @@ func IsTargetDb(target string) (string, bool, string, bool) {
 	return dbName, false, dbNamespace, dbNameSpaceExist
 }
 
+// newRedisClient returns a client to a SONiC DB, observing the latency of
+// its redis calls.
+func newRedisClient(dbName string, opt *redis.Options) *redis.Client {
+	redisDb := redis.NewClient(opt)
+	redisDb.WrapProcess(func(process func(redis.Cmder) error) func(redis.Cmder) error {
+		return func(cmd redis.Cmder) error {
+			defer redisCallLatency.ObserveSince(time.Now(), dbName, cmd.Name())
+			return process(cmd)
+		}
+	})
+	redisDb.WrapProcessPipeline(func(process func([]redis.Cmder) error) func([]redis.Cmder) error {
+		return func(cmds []redis.Cmder) error {
+			defer redisCallLatency.ObserveSince(time.Now(), dbName, "pipeline")
+			return process(cmds)
+		}
+	})
+	return redisDb
+}
+
 // For testing only
 func useRedisTcpClient() {
 	if !UseRedisLocalTcpPort {
@@ -412,7 +713,7 @@ func useRedisTcpClient() {
 		for dbName, dbn := range spb.Target_value {
 			if dbName != "OTHERS" {
 				// DB connector for direct redis operation
-				redisDb := redis.NewClient(&redis.Options{
+				redisDb := newRedisClient(dbName, &redis.Options{
 					Network:     "tcp",
 					Addr:        sdcfg.GetDbTcpAddr(dbName, dbNamespace),
 					Password:    "", // no password set
@@ -432,7 +733,7 @@ func init() {
 		for dbName, dbn := range spb.Target_value {
 			if dbName != "OTHERS" {
 				// DB connector for direct redis operation
-				redisDb := redis.NewClient(&redis.Options{
+				redisDb := newRedisClient(dbName, &redis.Options{
 					Network:     "unix",
 					Addr:        sdcfg.GetDbSock(dbName, dbNamespace),
 					Password:    "", // no password set
@@ -736,7 +1037,9 @@ func msi2TypedValue(msi map[string]interface{}) (*gnmipb.TypedValue, error) {
 		}}, nil
 }
 
-func tableData2TypedValue(tblPaths []tablePath, op *string) (*gnmipb.TypedValue, error) {
+// tableData reads the data of the table paths: the string value of a field,
+// or a map of the keys and fields of the tables.
+func tableData(tblPaths []tablePath, op *string) (interface{}, error) {
 	var useKey bool
 	msi := make(map[string]interface{})
 	for _, tblPath := range tblPaths {
@@ -761,10 +1064,7 @@ func tableData2TypedValue(tblPaths []tablePath, op *string) (*gnmipb.TypedValue,
 					return nil, err
 				}
 				// TODO: support multiple table paths
-				return &gnmipb.TypedValue{
-					Value: &gnmipb.TypedValue_StringVal{
-						StringVal: val,
-					}}, nil
+				return val, nil
 			}
 		}
 
@@ -773,16 +1073,28 @@ func tableData2TypedValue(tblPaths []tablePath, op *string) (*gnmipb.TypedValue,
 			return nil, err
 		}
 	}
-	return msi2TypedValue(msi)
+	return msi, nil
+}
+
+// put enqueues a value for the subscribe client, counting it.
+func (c *DbClient) put(val Value) error {
+	err := c.q.Put(val)
+	if err == nil {
+		atomic.AddInt64(&c.sendMsg, 1)
+		dbClientMsgs.Inc(c.prefix.GetTarget())
+	}
+	return err
 }
 
 func enqueueFatalMsg(c *DbClient, msg string) {
+	atomic.AddInt64(&c.errors, 1)
+	dbClientErrors.Inc(c.prefix.GetTarget())
 	putFatalMsg(c.q, msg)
 }
 
 func putFatalMsg(q *queue.PriorityQueue, msg string) {
 	q.Put(Value{
-		&spb.Value{
+		Value: &spb.Value{
 			Timestamp: time.Now().UnixNano(),
 			Fatal:     msg,
 		},
@@ -793,24 +1105,29 @@ func putFatalMsg(q *queue.PriorityQueue, msg string) {
 // It handles queries like "COUNTERS/Ethernet*/xyz" where the path translates to a field  in multiple tables.
 // For SAMPLE mode, it would send periodically regardless of change.
 // However, if `updateOnly` is true, the payload would include only the changed fields.
-// For ON_CHANGE mode, it would send only if the value has changed since the last update.
-func dbFieldMultiSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interval time.Duration, updateOnly bool) {
+// For ON_CHANGE mode, the fields are read when the keyspace notifications tell
+// their keys changed, and sent only if the value has changed since the last update.
+// With a heartbeat, all the values are sent again when nothing was sent for that long.
+func dbFieldMultiSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interval time.Duration, updateOnly bool, opts streamOptions) {
 	defer c.w.Done()
 
 	tblPaths := c.pathG2S[gnmiPath]
+	allPaths := make([]int, len(tblPaths))
+	for i := range allPaths {
+		allPaths[i] = i
+	}
 
 	// Init the path to value map, it saves the previous value
 	path2ValueMap := make(map[tablePath]string)
 
-	readVal := func() map[string]interface{} {
+	// readVal reads the values of the table paths, only the changed ones
+	// unless full, and tells whether any changed.
+	readVal := func(paths []int, full bool) (map[string]interface{}, bool) {
 		msi := make(map[string]interface{})
-		for _, tblPath := range tblPaths {
-			var key string
-			if tblPath.tableKey != "" {
-				key = tblPath.tableName + tblPath.delimitor + tblPath.tableKey
-			} else {
-				key = tblPath.tableName
-			}
+		changed := false
+		for _, i := range paths {
+			tblPath := tblPaths[i]
+			key := dbSetRedisKey(tblPath, tblPath.tableKey)
 			// run redis get directly for field value
 			redisDb := Target2RedisDb[tblPath.dbNamespace][tblPath.dbName]
 			val, err := redisDb.HGet(key, tblPath.field).Result()
@@ -828,34 +1145,31 @@ func dbFieldMultiSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, in
 
 			// This value was saved before and it hasn't changed since then
 			_, valueMapped := path2ValueMap[tblPath]
-			if (onChange || updateOnly) && valueMapped && val == path2ValueMap[tblPath] {
-				continue
+			if valueMapped && val == path2ValueMap[tblPath] {
+				if (onChange || updateOnly) && !full {
+					continue
+				}
+			} else {
+				changed = true
 			}
 
 			path2ValueMap[tblPath] = val
-			fv := map[string]string{tblPath.jsonField: val}
+			fv := map[string]interface{}{tblPath.jsonField: val}
 			msi[tblPath.jsonTableKey] = fv
 			log.V(6).Infof("new value %v for %v", val, tblPath)
 		}
 
-		return msi
+		return msi, changed
 	}
 
 	sendVal := func(msi map[string]interface{}) error {
-		val, err := msi2TypedValue(msi)
+		val, err := c.newValue(gnmiPath, msi)
 		if err != nil {
 			enqueueFatalMsg(c, err.Error())
 			return err
 		}
 
-		spbv := &spb.Value{
-			Prefix:    c.prefix,
-			Path:      gnmiPath,
-			Timestamp: time.Now().UnixNano(),
-			Val:       val,
-		}
-
-		if err = c.q.Put(Value{spbv}); err != nil {
+		if err = c.put(val); err != nil {
 			log.V(1).Infof("Queue error:  %v", err)
 			return err
 		}
@@ -863,26 +1177,73 @@ func dbFieldMultiSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, in
 		return nil
 	}
 
-	msi := readVal()
+	// Subscribe to the keys before the initial read, not to miss a change
+	var changedPaths chan []int
+	if onChange {
+		changedPaths = make(chan []int)
+		if err := dbFieldKeyspaceSubscribe(c, tblPaths, changedPaths); err != nil {
+			log.V(1).Infof("%v", err)
+			enqueueFatalMsg(c, err.Error())
+			c.synced.Done()
+			return
+		}
+	}
+
+	msi, _ := readVal(allPaths, true)
 	if err := sendVal(msi); err != nil {
 		c.synced.Done()
 		return
 	}
 	c.synced.Done()
 
+	intervalTicker := make(<-chan time.Time)
+	heartbeatTicker := make(<-chan time.Time)
+	heartbeatTicks := 0
+	if !onChange {
+		heartbeatTicks = opts.heartbeatTicks(false, interval)
+	}
+	unsentTicks := 0
+	sent := true
 	for {
+		if !onChange {
+			intervalTicker = IntervalTicker(interval)
+		} else if opts.heartbeat > 0 && sent {
+			// The heartbeat restarts after each update sent.
+			heartbeatTicker = IntervalTicker(opts.heartbeat)
+		}
+		sent = false
+
 		select {
 		case <-c.channel:
 			log.V(1).Infof("Stopping dbFieldMultiSubscribe routine for Client %s ", c)
 			return
-		case <-IntervalTicker(interval):
-			msi := readVal()
+		case paths := <-changedPaths:
+			if msi, changed := readVal(paths, false); changed {
+				if err := sendVal(msi); err != nil {
+					log.Errorf("Queue error:  %v", err)
+					return
+				}
+				sent = true
+			}
+		case <-heartbeatTicker:
+			log.V(1).Infof("heartbeat received for %v", gnmiPath)
+			msi, _ := readVal(allPaths, true)
+			if err := sendVal(msi); err != nil {
+				log.Errorf("Queue error:  %v", err)
+				return
+			}
+			sent = true
+		case <-intervalTicker:
+			unsentTicks++
+			heartbeat := heartbeatTicks > 0 && unsentTicks >= heartbeatTicks
+			msi, changed := readVal(allPaths, heartbeat)
 
-			if onChange == false || len(msi) != 0 {
+			if changed || heartbeat || !opts.suppressRedundant {
 				if err := sendVal(msi); err != nil {
 					log.Errorf("Queue error:  %v", err)
 					return
 				}
+				unsentTicks = 0
 			}
 		}
 	}
@@ -891,21 +1252,17 @@ func dbFieldMultiSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, in
 // dbFieldSubscribe would read a field from a single table and put to output queue.
 // Handles queries like "COUNTERS/Ethernet0/xyz" where the path translates to a field in a table.
 // For SAMPLE mode, it would send periodically regardless of change.
-// For ON_CHANGE mode, it would send only if the value has changed since the last update.
-func dbFieldSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interval time.Duration) {
+// For ON_CHANGE mode, the field is read when the keyspace notifications tell
+// its key changed, and sent only if the value has changed since the last update.
+// With a heartbeat, the value is sent again when it was not sent for that long.
+func dbFieldSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interval time.Duration, opts streamOptions) {
 	defer c.w.Done()
 
 	tblPaths := c.pathG2S[gnmiPath]
 	tblPath := tblPaths[0]
 	// run redis get directly for field value
 	redisDb := Target2RedisDb[tblPath.dbNamespace][tblPath.dbName]
-
-	var key string
-	if tblPath.tableKey != "" {
-		key = tblPath.tableName + tblPath.delimitor + tblPath.tableKey
-	} else {
-		key = tblPath.tableName
-	}
+	key := dbSetRedisKey(tblPath, tblPath.tableKey)
 
 	readVal := func() string {
 		newVal, err := redisDb.HGet(key, tblPath.field).Result()
@@ -921,18 +1278,8 @@ func dbFieldSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interva
 	}
 
 	sendVal := func(newVal string) error {
-		spbv := &spb.Value{
-			Prefix:    c.prefix,
-			Path:      gnmiPath,
-			Timestamp: time.Now().UnixNano(),
-			Val: &gnmipb.TypedValue{
-				Value: &gnmipb.TypedValue_StringVal{
-					StringVal: newVal,
-				},
-			},
-		}
-
-		if err := c.q.Put(Value{spbv}); err != nil {
+		v, _ := c.newValue(gnmiPath, newVal)
+		if err := c.put(v); err != nil {
 			log.V(1).Infof("Queue error:  %v", err)
 			return err
 		}
@@ -940,35 +1287,350 @@ func dbFieldSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interva
 		return nil
 	}
 
+	// Subscribe to the key before the initial read, not to miss a change
+	var changedPaths chan []int
+	if onChange {
+		changedPaths = make(chan []int)
+		if err := dbFieldKeyspaceSubscribe(c, tblPaths, changedPaths); err != nil {
+			log.V(1).Infof("%v", err)
+			enqueueFatalMsg(c, err.Error())
+			c.synced.Done()
+			return
+		}
+	}
+
 	// Read the initial value and signal sync after sending it
 	val := readVal()
 	err := sendVal(val)
 	if err != nil {
-		putFatalMsg(c.q, err.Error())
+		enqueueFatalMsg(c, err.Error())
 		c.synced.Done()
 		return
 	}
 	c.synced.Done()
 
+	intervalTicker := make(<-chan time.Time)
+	heartbeatTicker := make(<-chan time.Time)
+	heartbeatTicks := 0
+	if !onChange {
+		heartbeatTicks = opts.heartbeatTicks(false, interval)
+	}
+	unsentTicks := 0
+	sent := true
 	for {
+		if !onChange {
+			intervalTicker = IntervalTicker(interval)
+		} else if opts.heartbeat > 0 && sent {
+			// The heartbeat restarts after each update sent.
+			heartbeatTicker = IntervalTicker(opts.heartbeat)
+		}
+		sent = false
+
 		select {
 		case <-c.channel:
 			log.V(1).Infof("Stopping dbFieldSubscribe routine for Client %s ", c)
 			return
-		case <-IntervalTicker(interval):
+		case <-changedPaths:
+			if newVal := readVal(); newVal != val {
+				if err = sendVal(newVal); err != nil {
+					log.V(1).Infof("Queue error:  %v", err)
+					return
+				}
+				val = newVal
+				sent = true
+			}
+		case <-heartbeatTicker:
+			log.V(1).Infof("heartbeat received for %v", gnmiPath)
+			val = readVal()
+			if err = sendVal(val); err != nil {
+				log.V(1).Infof("Queue error:  %v", err)
+				return
+			}
+			sent = true
+		case <-intervalTicker:
+			unsentTicks++
+			heartbeat := heartbeatTicks > 0 && unsentTicks >= heartbeatTicks
 			newVal := readVal()
 
-			if onChange == false || newVal != val {
+			if newVal != val || heartbeat || !opts.suppressRedundant {
 				if err = sendVal(newVal); err != nil {
 					log.V(1).Infof("Queue error:  %v", err)
 					return
 				}
 				val = newVal
+				unsentTicks = 0
 			}
 		}
 	}
 }
 
+// dbFieldKeyspace is the keyspace of the keys of field subscriptions in a DB.
+type dbFieldKeyspace struct {
+	redisDb  *redis.Client
+	channels []string
+	// Indexes of the table paths by keyspace notification channel
+	paths map[string][]int
+}
+
+// dbFieldKeyspaceSubscribe subscribes to the keyspace notifications of the
+// keys of the table paths of field subscriptions, and sends the indexes of
+// the table paths of each changed key on changed until the client stops.
+// The field subscriptions of a client share one pubsub connection by DB.
+func dbFieldKeyspaceSubscribe(c *DbClient, tblPaths []tablePath, changed chan<- []int) error {
+	var keyspaces []*dbFieldKeyspace
+	for i, tblPath := range tblPaths {
+		redisDb := Target2RedisDb[tblPath.dbNamespace][tblPath.dbName]
+		var ks *dbFieldKeyspace
+		for _, k := range keyspaces {
+			if k.redisDb == redisDb {
+				ks = k
+			}
+		}
+		if ks == nil {
+			ks = &dbFieldKeyspace{redisDb: redisDb, paths: map[string][]int{}}
+			keyspaces = append(keyspaces, ks)
+		}
+		channel := "__keyspace@" + strconv.Itoa(sdcfg.GetDbId(tblPath.dbName, tblPath.dbNamespace)) + "__:"
+		channel += dbSetRedisKey(tblPath, tblPath.tableKey)
+		if _, ok := ks.paths[channel]; !ok {
+			ks.channels = append(ks.channels, channel)
+		}
+		ks.paths[channel] = append(ks.paths[channel], i)
+	}
+
+	for _, ks := range keyspaces {
+		sub := &dbKeyspaceSub{
+			paths:   ks.paths,
+			changed: changed,
+			pending: map[int]bool{},
+			notify:  make(chan struct{}, 1),
+		}
+		if err := c.keyspaceMux(ks.redisDb).add(c, sub, ks.channels); err != nil {
+			return fmt.Errorf("subscribe to %v failed: %v", ks.channels, err)
+		}
+		log.V(2).Infof("Subscribe succeeded for %v", ks.channels)
+		go sub.forward(c)
+	}
+	return nil
+}
+
+// dbKeyspaceSub receives the keyspace notifications of the keys of a field
+// subscription. The indexes of the changed table paths are merged until
+// the subscription reads them, not to block the other subscriptions of the
+// shared pubsub.
+type dbKeyspaceSub struct {
+	// Indexes of the table paths by keyspace notification channel
+	paths   map[string][]int
+	changed chan<- []int
+
+	mu      sync.Mutex
+	pending map[int]bool
+	notify  chan struct{}
+}
+
+// post adds the indexes of changed table paths to the pending ones.
+func (s *dbKeyspaceSub) post(paths []int) {
+	s.mu.Lock()
+	for _, i := range paths {
+		s.pending[i] = true
+	}
+	s.mu.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// forward sends the pending indexes on changed until the client stops.
+func (s *dbKeyspaceSub) forward(c *DbClient) {
+	for {
+		select {
+		case <-s.notify:
+		case <-c.channel:
+			return
+		}
+		s.mu.Lock()
+		paths := make([]int, 0, len(s.pending))
+		for i := range s.pending {
+			paths = append(paths, i)
+		}
+		s.pending = map[int]bool{}
+		s.mu.Unlock()
+
+		select {
+		case s.changed <- paths:
+		case <-c.channel:
+			return
+		}
+	}
+}
+
+// allPaths returns the indexes of all the table paths of the subscription.
+func (s *dbKeyspaceSub) allPaths() []int {
+	var all []int
+	for _, p := range s.paths {
+		all = append(all, p...)
+	}
+	return all
+}
+
+// dbKeyspaceMux shares the pubsub connection of a DB among the field
+// subscriptions of a client, dispatching the notifications by channel.
+// Notifications are lost while the connection is down, so all the table
+// paths are sent to reconcile once it is subscribed again.
+type dbKeyspaceMux struct {
+	pubsub *redis.PubSub
+
+	mu sync.Mutex
+	// Subscriptions by keyspace notification channel
+	subs map[string][]*dbKeyspaceSub
+	all  []*dbKeyspaceSub
+	// Channels subscribed, with the ones not confirmed yet closing their
+	// pending channel on confirmation. The keyspace channels of the keys
+	// are exact names, subscribed without pattern matching.
+	channels map[string]bool
+	pending  map[string]chan struct{}
+}
+
+// keyspaceMux returns the keyspace notifications of redisDb shared by the
+// field subscriptions of the client, receiving them until the client stops.
+func (c *DbClient) keyspaceMux(redisDb *redis.Client) *dbKeyspaceMux {
+	c.keyspaceMu.Lock()
+	defer c.keyspaceMu.Unlock()
+	if m, ok := c.keyspaces[redisDb]; ok {
+		return m
+	}
+	m := &dbKeyspaceMux{
+		pubsub:   redisDb.Subscribe(),
+		subs:     map[string][]*dbKeyspaceSub{},
+		channels: map[string]bool{},
+		pending:  map[string]chan struct{}{},
+	}
+	if c.keyspaces == nil {
+		c.keyspaces = map[*redis.Client]*dbKeyspaceMux{}
+	}
+	c.keyspaces[redisDb] = m
+	go m.receive(c)
+	return m
+}
+
+// add dispatches the notifications of the channels of sub to it, and
+// subscribes to the channels not subscribed yet. It returns once all the
+// channels are subscribed, not to miss a change read after it.
+func (m *dbKeyspaceMux) add(c *DbClient, sub *dbKeyspaceSub, channels []string) error {
+	var subscribe []string
+	var waits []chan struct{}
+	m.mu.Lock()
+	for channel := range sub.paths {
+		m.subs[channel] = append(m.subs[channel], sub)
+	}
+	m.all = append(m.all, sub)
+	for _, p := range channels {
+		if !m.channels[p] {
+			m.channels[p] = true
+			m.pending[p] = make(chan struct{})
+			subscribe = append(subscribe, p)
+		}
+		if wait, ok := m.pending[p]; ok {
+			waits = append(waits, wait)
+		}
+	}
+	m.mu.Unlock()
+
+	var err error
+	if len(subscribe) > 0 {
+		err = m.pubsub.Subscribe(subscribe...)
+	}
+	timeout := time.After(time.Second)
+	for _, wait := range waits {
+		if err != nil {
+			break
+		}
+		select {
+		case <-wait:
+		case <-timeout:
+			err = fmt.Errorf("no confirmation")
+		case <-c.channel:
+			err = fmt.Errorf("client stopped")
+		}
+	}
+	if err != nil {
+		// Subscribe again to the failed channels on the next add.
+		m.mu.Lock()
+		for _, p := range subscribe {
+			if _, ok := m.pending[p]; ok {
+				delete(m.pending, p)
+				delete(m.channels, p)
+			}
+		}
+		m.mu.Unlock()
+	}
+	return err
+}
+
+// receive dispatches the keyspace notifications until the client stops.
+func (m *dbKeyspaceMux) receive(c *DbClient) {
+	defer m.pubsub.Close()
+
+	disconnected := false
+	for {
+		select {
+		case <-c.channel:
+			return
+		default:
+		}
+
+		msgi, err := m.pubsub.ReceiveTimeout(time.Millisecond * 500)
+		if err != nil {
+			if neterr, ok := err.(net.Error); ok && neterr.Timeout() {
+				continue
+			}
+			// The pubsub reconnects and subscribes again on the next receive
+			log.V(2).Infof("pubsub.ReceiveTimeout err %v", err)
+			disconnected = true
+			time.Sleep(time.Millisecond * 500)
+			continue
+		}
+
+		m.mu.Lock()
+		switch msg := msgi.(type) {
+		case *redis.Message:
+			for _, sub := range m.subs[msg.Channel] {
+				sub.post(sub.paths[msg.Channel])
+			}
+		case *redis.Subscription:
+			if wait, ok := m.pending[msg.Channel]; ok {
+				close(wait)
+				delete(m.pending, msg.Channel)
+			}
+			// Subscribed again after a reconnection: read all the fields,
+			// their changes meanwhile were not notified.
+			if disconnected {
+				log.V(1).Infof("Reconciling %v subscriptions after reconnection", len(m.all))
+				for _, sub := range m.all {
+					sub.post(sub.allPaths())
+				}
+				disconnected = false
+			}
+		}
+		m.mu.Unlock()
+	}
+}
+
+// redisPatternEscape escapes the glob characters of a redis key, to
+// psubscribe to its exact channel.
+func redisPatternEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 type redisSubData struct {
 	tblPath   tablePath
 	pubsub    *redis.PubSub
@@ -1065,7 +1727,8 @@ func dbSingleTableKeySubscribe(c *DbClient, rsd redisSubData, updateChannel chan
 // dbTableKeySubscribe subscribes to tables using a table keys.
 // Handles queries like "COUNTERS/Ethernet0" or "COUNTERS/Ethernet*"
 // This function handles both ON_CHANGE and SAMPLE modes. "interval" being 0 is interpreted as ON_CHANGE mode.
-func dbTableKeySubscribe(c *DbClient, gnmiPath *gnmipb.Path, interval time.Duration, updateOnly bool) {
+// With a heartbeat, all the data is read and sent again when nothing was sent for that long.
+func dbTableKeySubscribe(c *DbClient, gnmiPath *gnmipb.Path, interval time.Duration, updateOnly bool, opts streamOptions) {
 	defer c.w.Done()
 
 	tblPaths := c.pathG2S[gnmiPath]
@@ -1090,25 +1753,29 @@ func dbTableKeySubscribe(c *DbClient, gnmiPath *gnmipb.Path, interval time.Durat
 
 	// Helper to send hash data over the stream
 	sendMsiData := func(msiData map[string]interface{}) error {
-		val, err := msi2TypedValue(msiData)
+		val, err := c.newValue(gnmiPath, msiData)
 		if err != nil {
 			return err
 		}
 
-		var spbv *spb.Value
-		spbv = &spb.Value{
-			Prefix:    c.prefix,
-			Path:      gnmiPath,
-			Timestamp: time.Now().UnixNano(),
-			Val:       val,
-		}
-		if err = c.q.Put(Value{spbv}); err != nil {
+		if err = c.put(val); err != nil {
 			return fmt.Errorf("Queue error:  %v", err)
 		}
 
 		return nil
 	}
 
+	// Helper to read all the data of the tables
+	readAll := func() (map[string]interface{}, error) {
+		msi := make(map[string]interface{})
+		for _, tblPath := range tblPaths {
+			if err := tableData2Msi(&tblPath, false, nil, &msi); err != nil {
+				return nil, err
+			}
+		}
+		return msi, nil
+	}
+
 	// Go through the paths and identify the tables to register.
 	for _, tblPath := range tblPaths {
 		// Subscribe to keyspace notification
@@ -1178,12 +1845,22 @@ func dbTableKeySubscribe(c *DbClient, gnmiPath *gnmipb.Path, interval time.Durat
 	// Listen on updates from tables.
 	// Depending on the interval, send the updates every interval or on change only.
 	intervalTicker := make(<-chan time.Time)
+	heartbeatTicker := make(<-chan time.Time)
+	heartbeatTicks := 0
+	if interval > 0 {
+		heartbeatTicks = opts.heartbeatTicks(false, interval)
+	}
+	unsentTicks := 0
+	changed := false
 	for {
 
 		// The interval ticker ticks only when the interval is non-zero.
 		// Otherwise (e.g. on-change mode) it would never tick.
 		if interval > 0 {
 			intervalTicker = IntervalTicker(interval)
+		} else if opts.heartbeat > 0 {
+			// The heartbeat restarts after each update sent.
+			heartbeatTicker = IntervalTicker(opts.heartbeat)
 		}
 
 		select {
@@ -1198,16 +1875,42 @@ func dbTableKeySubscribe(c *DbClient, gnmiPath *gnmipb.Path, interval time.Durat
 			} else {
 				// Update the overall table, it will be sent when the interval ticks.
 				for k := range updatedTable {
+					changed = changed || !reflect.DeepEqual(msiAll[k], updatedTable[k])
 					msiAll[k] = updatedTable[k]
 				}
 			}
+		case <-heartbeatTicker:
+			log.V(1).Infof("heartbeat received for %v", gnmiPath)
+			msi, err := readAll()
+			if err == nil {
+				err = sendMsiData(msi)
+			}
+			if err != nil {
+				handleFatalMsg(err.Error())
+				return
+			}
 		case <-intervalTicker:
 			log.V(1).Infof("ticker received: %v", len(msiAll))
 
-			if err := sendMsiData(msiAll); err != nil {
+			unsentTicks++
+			msi := msiAll
+			if heartbeatTicks > 0 && unsentTicks >= heartbeatTicks {
+				var err error
+				if msi, err = readAll(); err != nil {
+					handleFatalMsg(err.Error())
+					return
+				}
+			} else if opts.suppressRedundant && !changed {
+				log.V(6).Infof("Redundant data of %v suppressed", gnmiPath)
+				continue
+			}
+
+			if err := sendMsiData(msi); err != nil {
 				handleFatalMsg(err.Error())
 				return
 			}
+			unsentTicks = 0
+			changed = false
 
 			// Clear the payload so that next time it will send only updates
 			if updateOnly {
@@ -1222,10 +1925,407 @@ func dbTableKeySubscribe(c *DbClient, gnmiPath *gnmipb.Path, interval time.Durat
 	}
 }
 
+// writableDbTargets lists the DB targets which accept gNMI Set requests.
+var writableDbTargets = map[string]bool{
+	"CONFIG_DB": true,
+	"APPL_DB":   true,
+	"STATE_DB":  true,
+}
+
+// dbSetOp is a single redis operation derived from a gNMI Set path.
+type dbSetOp struct {
+	tblPath tablePath
+	// fvs holds the field value pairs of every key to be written,
+	// indexed by table key. Empty for delete operations.
+	fvs map[string]map[string]interface{}
+}
+
+// dbSetMaxAttempts bounds the attempts of a Set transaction whose watched
+// keys are modified concurrently.
+const dbSetMaxAttempts = 5
+
+// Set implements gNMI Set for DB targets. The path scheme is the same as
+// the one used for Get and Subscribe, e.g. CONFIG_DB/PORT/Ethernet0/admin_status.
+// Deletes are applied first, then replaces and then updates, all within
+// a single redis MULTI/EXEC transaction. The keys looked up to resolve the
+// operations are WATCHed, and the transaction is retried when they change
+// before the EXEC. Redis failures are reported with the Internal code,
+// invalid operations in a common_utils.SetError.
 func (c *DbClient) Set(delete []*gnmipb.Path, replace []*gnmipb.Update, update []*gnmipb.Update) error {
+	target := c.prefix.GetTarget()
+	targetDbName, targetDbNameValid, targetDbNameSpace, _ := IsTargetDb(target)
+	if !targetDbNameValid {
+		return fmt.Errorf("Invalid target dbName %v", targetDbName)
+	}
+	if !writableDbTargets[targetDbName] {
+		return fmt.Errorf("Set is not supported for target %v", targetDbName)
+	}
+	dbNamespace, ok := sdcfg.GetDbNamespaceFromTarget(targetDbNameSpace)
+	if !ok {
+		return fmt.Errorf("Invalid target dbNameSpace %v", targetDbNameSpace)
+	}
+	redisDb, ok := Target2RedisDb[dbNamespace][targetDbName]
+	if !ok {
+		return fmt.Errorf("Redis Client not present for dbName %v dbNamespace %v", targetDbName, dbNamespace)
+	}
+	separator, _ := GetTableKeySeparator(targetDbName, dbNamespace)
+	base := tablePath{
+		dbNamespace: dbNamespace,
+		dbName:      targetDbName,
+		delimitor:   separator,
+	}
+
+	// Resolve all operations first, so that every invalid one is reported.
+	setErr := common_utils.SetError{
+		Delete:  make([]error, len(delete)),
+		Replace: make([]error, len(replace)),
+		Update:  make([]error, len(update)),
+	}
+	failed := false
+	var deletePaths []*gnmipb.Path
+	var replaceOps, updateOps []dbSetOp
+	for i, path := range delete {
+		path = gnmiFullPath(c.prefix, path)
+		if _, _, err := dbSetPathElems(path); err != nil {
+			setErr.Delete[i] = err
+			failed = true
+		}
+		deletePaths = append(deletePaths, path)
+	}
+	for i, u := range replace {
+		op, err := dbSetUpdateOp(base, gnmiFullPath(c.prefix, u.GetPath()), u.GetVal())
+		if err != nil {
+			setErr.Replace[i] = err
+			failed = true
+		}
+		replaceOps = append(replaceOps, op)
+	}
+	for i, u := range update {
+		op, err := dbSetUpdateOp(base, gnmiFullPath(c.prefix, u.GetPath()), u.GetVal())
+		if err != nil {
+			setErr.Update[i] = err
+			failed = true
+		}
+		updateOps = append(updateOps, op)
+	}
+	if failed {
+		log.V(2).Infof("Set on %v rejected: %v", target, setErr.Error())
+		return &setErr
+	}
+
+	var err error
+	for i := 0; i < dbSetMaxAttempts; i++ {
+		err = redisDb.Watch(func(tx *redis.Tx) error {
+			return dbSetApply(tx, base, deletePaths, replaceOps, updateOps)
+		})
+		if err != redis.TxFailedErr {
+			break
+		}
+		log.V(2).Infof("Set transaction on %v raced with a concurrent write, retrying", target)
+	}
+	if err == redis.TxFailedErr {
+		return status.Errorf(codes.Aborted, "Set transaction on %v failed: keys modified concurrently", target)
+	}
+	if err != nil {
+		log.V(2).Infof("Set transaction on %v failed: %v", target, err)
+		return status.Errorf(codes.Internal, "Set transaction on %v failed: %v", target, err)
+	}
 	return nil
 }
 
+// dbSetApply resolves the delete paths and runs the Set operations in a
+// MULTI/EXEC transaction of tx. The keys of the tables deleted or replaced
+// as a whole are collected with SCAN before the MULTI and watched, so that
+// a concurrent change of one of them fails the transaction rather than
+// surviving it.
+func dbSetApply(tx *redis.Tx, base tablePath, deletePaths []*gnmipb.Path, replaceOps []dbSetOp, updateOps []dbSetOp) error {
+	var deleteOps []dbSetOp
+	for _, path := range deletePaths {
+		op, err := dbSetDeleteOp(tx, base, path)
+		if err != nil {
+			return err
+		}
+		deleteOps = append(deleteOps, op)
+	}
+	tableKeys := make(map[string][]string)
+	for _, ops := range [][]dbSetOp{deleteOps, replaceOps} {
+		for _, op := range ops {
+			tp := op.tblPath
+			if tp.field != "" || tp.tableKey != "" {
+				continue
+			}
+			if _, ok := tableKeys[tp.tableName]; ok {
+				continue
+			}
+			keys, err := dbScanKeys(tx, redisPatternEscape(tp.tableName+tp.delimitor)+"*")
+			if err != nil {
+				return fmt.Errorf("redis Scan failed for table %v: %v", tp.tableName, err)
+			}
+			if len(keys) > 0 {
+				if err := tx.Watch(keys...).Err(); err != nil {
+					return fmt.Errorf("redis Watch failed for table %v: %v", tp.tableName, err)
+				}
+			}
+			tableKeys[tp.tableName] = keys
+		}
+	}
+
+	_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+		for _, op := range deleteOps {
+			tp := op.tblPath
+			switch {
+			case tp.field != "":
+				pipe.HDel(dbSetRedisKey(tp, tp.tableKey), tp.field)
+			case tp.tableKey != "":
+				pipe.Del(dbSetRedisKey(tp, tp.tableKey))
+			default:
+				dbSetDelTable(pipe, tableKeys[tp.tableName])
+			}
+		}
+		for _, op := range replaceOps {
+			tp := op.tblPath
+			switch {
+			case tp.field != "":
+				// Replacing a single field is the same as updating it.
+			case tp.tableKey != "":
+				pipe.Del(dbSetRedisKey(tp, tp.tableKey))
+			default:
+				dbSetDelTable(pipe, tableKeys[tp.tableName])
+			}
+			dbSetWrite(pipe, tp, op.fvs)
+		}
+		for _, op := range updateOps {
+			dbSetWrite(pipe, op.tblPath, op.fvs)
+		}
+		return nil
+	})
+	return err
+}
+
+// dbSetRedisKey returns the redis key of the given table key. An empty
+// table key refers to the table itself.
+func dbSetRedisKey(tp tablePath, tableKey string) string {
+	if tableKey == "" {
+		return tp.tableName
+	}
+	return tp.tableName + tp.delimitor + tableKey
+}
+
+// dbSetWrite queues the HMSET of all field value pairs of op into pipe.
+func dbSetWrite(pipe redis.Pipeliner, tp tablePath, fvs map[string]map[string]interface{}) {
+	for tableKey, fv := range fvs {
+		if len(fv) == 0 {
+			// Same as swsscommon, keep keys without any field with a NULL field.
+			fv = map[string]interface{}{"NULL": "NULL"}
+		}
+		pipe.HMSet(dbSetRedisKey(tp, tableKey), fv)
+	}
+}
+
+// dbSetDelTable queues the deletion of the keys of a table into pipe, in
+// batches of bounded size.
+func dbSetDelTable(pipe redis.Pipeliner, keys []string) {
+	for i := 0; i < len(keys); i += 1000 {
+		end := i + 1000
+		if end > len(keys) {
+			end = len(keys)
+		}
+		pipe.Del(keys[i:end]...)
+	}
+}
+
+// dbScanKeys returns the keys matching pattern. Unlike KEYS, SCAN walks
+// the keyspace in batches, without blocking redis on large DBs.
+func dbScanKeys(c redis.Cmdable, pattern string) ([]string, error) {
+	var keys []string
+	seen := make(map[string]bool)
+	iter := c.Scan(0, pattern, 1000).Iterator()
+	for iter.Next() {
+		// SCAN may return a key more than once.
+		if key := iter.Val(); !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys, iter.Err()
+}
+
+// dbSetPathElems returns the table name and the remaining elements of path.
+func dbSetPathElems(path *gnmipb.Path) (string, []string, error) {
+	var elems []string
+	for _, elem := range path.GetElem() {
+		elems = append(elems, elem.GetName())
+	}
+	if len(elems) == 0 {
+		return "", nil, fmt.Errorf("Invalid db table Path %v, table name is required", path)
+	}
+	return elems[0], elems[1:], nil
+}
+
+// dbSetDeleteOp resolves a delete path into a table, a key or a field.
+// The path may be:
+// <1> DB Table: all keys of the table are deleted
+// <2> DB Table Key [Key]: the key is deleted
+// <3> DB Table [Key [Key]] Field: the field is deleted
+// A path matching neither an existing key nor a field of an existing key is
+// a key which does not exist, its delete succeeds without effect.
+// The keys looked up are watched by tx.
+func dbSetDeleteOp(tx *redis.Tx, base tablePath, path *gnmipb.Path) (dbSetOp, error) {
+	op := dbSetOp{tblPath: base}
+	tableName, rest, err := dbSetPathElems(path)
+	if err != nil {
+		return op, err
+	}
+	op.tblPath.tableName = tableName
+
+	if len(rest) == 0 {
+		return op, nil
+	}
+
+	tableKey := strings.Join(rest, base.delimitor)
+	parentKey := strings.Join(rest[:len(rest)-1], base.delimitor)
+	err = tx.Watch(dbSetRedisKey(op.tblPath, tableKey), dbSetRedisKey(op.tblPath, parentKey)).Err()
+	if err != nil {
+		return op, status.Errorf(codes.Internal, "redis Watch op failed for %v: %v", tableKey, err)
+	}
+	n, err := tx.Exists(dbSetRedisKey(op.tblPath, tableKey)).Result()
+	if err != nil {
+		return op, status.Errorf(codes.Internal, "redis Exists op failed for %v: %v", tableKey, err)
+	}
+	if n == 0 {
+		n, err = tx.Exists(dbSetRedisKey(op.tblPath, parentKey)).Result()
+		if err != nil {
+			return op, status.Errorf(codes.Internal, "redis Exists op failed for %v: %v", parentKey, err)
+		}
+		if n == 1 {
+			op.tblPath.tableKey = parentKey
+			op.tblPath.field = rest[len(rest)-1]
+			return op, nil
+		}
+		log.V(4).Infof("Delete of missing key %v", dbSetRedisKey(op.tblPath, tableKey))
+	}
+	op.tblPath.tableKey = tableKey
+	return op, nil
+}
+
+// dbSetUpdateOp resolves a replace or update path and its value into the
+// field value pairs to be written. A JSON object value addresses a table
+// (object of keys) or a key (object of fields); any other value addresses
+// a single field, which is the last element of the path.
+func dbSetUpdateOp(base tablePath, path *gnmipb.Path, val *gnmipb.TypedValue) (dbSetOp, error) {
+	op := dbSetOp{tblPath: base, fvs: make(map[string]map[string]interface{})}
+	tableName, rest, err := dbSetPathElems(path)
+	if err != nil {
+		return op, err
+	}
+	op.tblPath.tableName = tableName
+
+	v, err := dbSetTypedValue(val)
+	if err != nil {
+		return op, fmt.Errorf("Invalid value for %v: %v", path, err)
+	}
+
+	obj, isObj := v.(map[string]interface{})
+	switch {
+	case isObj && len(rest) == 0: // table: {"key": {"field": "value"}}
+		for key, kv := range obj {
+			fields, ok := kv.(map[string]interface{})
+			if !ok {
+				return op, fmt.Errorf("Invalid value for %v: key %v is not an object", path, key)
+			}
+			fv, err := dbSetFieldValues(fields)
+			if err != nil {
+				return op, fmt.Errorf("Invalid value for %v: %v", path, err)
+			}
+			op.fvs[key] = fv
+		}
+	case isObj: // key: {"field": "value"}
+		op.tblPath.tableKey = strings.Join(rest, base.delimitor)
+		fv, err := dbSetFieldValues(obj)
+		if err != nil {
+			return op, fmt.Errorf("Invalid value for %v: %v", path, err)
+		}
+		op.fvs[op.tblPath.tableKey] = fv
+	case len(rest) == 0:
+		return op, fmt.Errorf("Invalid value for %v: table value must be an object", path)
+	default: // field: "value"
+		op.tblPath.tableKey = strings.Join(rest[:len(rest)-1], base.delimitor)
+		op.tblPath.field = rest[len(rest)-1]
+		s, err := dbSetFieldValue(v)
+		if err != nil {
+			return op, fmt.Errorf("Invalid value for %v: %v", path, err)
+		}
+		op.fvs[op.tblPath.tableKey] = map[string]interface{}{op.tblPath.field: s}
+	}
+	return op, nil
+}
+
+// dbSetTypedValue decodes a gNMI TypedValue of a Set request.
+func dbSetTypedValue(val *gnmipb.TypedValue) (interface{}, error) {
+	var v interface{}
+	switch tv := val.GetValue().(type) {
+	case *gnmipb.TypedValue_JsonIetfVal:
+		if err := json.Unmarshal(tv.JsonIetfVal, &v); err != nil {
+			return nil, err
+		}
+	case *gnmipb.TypedValue_JsonVal:
+		if err := json.Unmarshal(tv.JsonVal, &v); err != nil {
+			return nil, err
+		}
+	case *gnmipb.TypedValue_StringVal:
+		v = tv.StringVal
+	case *gnmipb.TypedValue_AsciiVal:
+		v = tv.AsciiVal
+	case *gnmipb.TypedValue_IntVal:
+		v = strconv.FormatInt(tv.IntVal, 10)
+	case *gnmipb.TypedValue_UintVal:
+		v = strconv.FormatUint(tv.UintVal, 10)
+	case *gnmipb.TypedValue_BoolVal:
+		v = strconv.FormatBool(tv.BoolVal)
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", val.GetValue())
+	}
+	return v, nil
+}
+
+// dbSetFieldValues converts a JSON object into redis field value pairs.
+func dbSetFieldValues(obj map[string]interface{}) (map[string]interface{}, error) {
+	fv := make(map[string]interface{}, len(obj))
+	for f, v := range obj {
+		s, err := dbSetFieldValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %v", f, err)
+		}
+		fv[f] = s
+	}
+	return fv, nil
+}
+
+// dbSetFieldValue converts a decoded JSON value into a redis field value.
+// Lists are stored comma separated as done for the "@" fields of CONFIG_DB.
+func dbSetFieldValue(v interface{}) (string, error) {
+	switch tv := v.(type) {
+	case string:
+		return tv, nil
+	case float64:
+		return strconv.FormatFloat(tv, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(tv), nil
+	case []interface{}:
+		items := make([]string, 0, len(tv))
+		for _, item := range tv {
+			s, err := dbSetFieldValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported field value %v", v)
+	}
+}
+
 func (c *DbClient) Capabilities() []gnmipb.ModelData {
 	return nil
 }
@@ -1233,12 +2333,13 @@ func (c *DbClient) Capabilities() []gnmipb.ModelData {
 // validateSampleInterval validates the sampling interval of the given subscription.
 func validateSampleInterval(sub *gnmipb.Subscription) (time.Duration, error) {
 	requestedInterval := time.Duration(sub.GetSampleInterval())
+	minInterval := minSampleInterval()
 	if requestedInterval == 0 {
 		// If the sample_interval is set to 0, the target MUST create the subscription
 		// and send the data with the lowest samplingInterval possible for the target
-		return MinSampleInterval, nil
-	} else if requestedInterval < MinSampleInterval {
-		return 0, fmt.Errorf("invalid interval: %v. It cannot be less than %v", requestedInterval, MinSampleInterval)
+		return minInterval, nil
+	} else if requestedInterval < minInterval {
+		return 0, fmt.Errorf("invalid interval: %v. It cannot be less than %v", requestedInterval, minInterval)
 	} else {
 		return requestedInterval, nil
 	}
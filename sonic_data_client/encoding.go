@@ -0,0 +1,254 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	spb "github.com/Azure/sonic-telemetry/proto"
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// ValToUpdates converts the value of a SONiC Value to gNMI updates in the
+// requested encoding. JSON and JSON_IETF values are sent as they are. For
+// PROTO encoding every leaf of JSON data is sent as its own update with a
+// scalar value; other values are already typed by their data client.
+func ValToUpdates(val *spb.Value, encoding gnmipb.Encoding) ([]*gnmipb.Update, error) {
+	path := val.GetPath()
+	if encoding != gnmipb.Encoding_PROTO {
+		return []*gnmipb.Update{{Path: path, Val: val.GetVal()}}, nil
+	}
+
+	var jv []byte
+	switch v := val.GetVal().GetValue().(type) {
+	case *gnmipb.TypedValue_JsonIetfVal:
+		jv = v.JsonIetfVal
+	case *gnmipb.TypedValue_JsonVal:
+		jv = v.JsonVal
+	default:
+		return []*gnmipb.Update{{Path: path, Val: val.GetVal()}}, nil
+	}
+
+	var data interface{}
+	dec := json.NewDecoder(bytes.NewReader(jv))
+	// Keep numbers as they are, 64 bit counters don't fit in a float64.
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("invalid json value for %v: %v", path, err)
+	}
+
+	var elems []*gnmipb.PathElem
+	if path != nil {
+		elems = path.GetElem()
+	}
+	data, elems = unwrapNode(data, elems)
+
+	var updates []*gnmipb.Update
+	if err := json2Updates(data, elems, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// TypedScalar converts a value read from the DB to a typed scalar. Unsigned
+// integers, like SAI counters, become UintVal, negative integers IntVal and
+// "true"/"false" BoolVal. Anything else is kept as StringVal.
+func TypedScalar(s string) *gnmipb.TypedValue {
+	// Only canonical forms are converted so that the string can be recovered.
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil && strconv.FormatUint(u, 10) == s {
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{UintVal: u}}
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(i, 10) == s {
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: i}}
+	}
+	if s == "true" || s == "false" {
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BoolVal{BoolVal: s == "true"}}
+	}
+	return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: s}}
+}
+
+// msi2Updates appends an update with a typed scalar value for every field of
+// DB data, a map of the keys and fields read from redis.
+func msi2Updates(msi map[string]interface{}, elems []*gnmipb.PathElem, updates []*gnmipb.Update) []*gnmipb.Update {
+	names := make([]string, 0, len(msi))
+	for name := range msi {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := appendElem(elems, &gnmipb.PathElem{Name: name})
+		switch v := msi[name].(type) {
+		case string:
+			updates = append(updates, &gnmipb.Update{Path: &gnmipb.Path{Elem: p}, Val: TypedScalar(v)})
+		case map[string]interface{}:
+			updates = msi2Updates(v, p, updates)
+		}
+	}
+	return updates
+}
+
+// localName strips the module prefix from a JSON_IETF member name.
+func localName(name string) string {
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// unwrapNode strips the member wrapping the requested node in JSON_IETF data,
+// e.g. {"openconfig-interfaces:config": {...}} for a path ending with config.
+// A list entry requested with its keys is unwrapped from its list as well.
+func unwrapNode(data interface{}, elems []*gnmipb.PathElem) (interface{}, []*gnmipb.PathElem) {
+	obj, ok := data.(map[string]interface{})
+	if !ok || len(obj) != 1 || len(elems) == 0 {
+		return data, elems
+	}
+	last := elems[len(elems)-1]
+	for name, member := range obj {
+		if localName(name) != localName(last.GetName()) {
+			return data, elems
+		}
+		if list, ok := member.([]interface{}); ok && len(last.GetKey()) > 0 && len(list) == 1 {
+			return list[0], elems
+		}
+		return member, elems
+	}
+	return data, elems
+}
+
+// appendElem returns a copy of elems with a new element appended.
+func appendElem(elems []*gnmipb.PathElem, elem *gnmipb.PathElem) []*gnmipb.PathElem {
+	p := make([]*gnmipb.PathElem, len(elems), len(elems)+1)
+	copy(p, elems)
+	return append(p, elem)
+}
+
+// json2Scalar converts a decoded JSON scalar to a typed value. JSON strings
+// are kept as StringVal, without the schema a string leaf like "100" can't
+// be told from a number.
+func json2Scalar(v interface{}) (*gnmipb.TypedValue, bool) {
+	switch tv := v.(type) {
+	case string:
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: tv}}, true
+	case json.Number:
+		return TypedScalar(tv.String()), true
+	case bool:
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BoolVal{BoolVal: tv}}, true
+	}
+	return nil, false
+}
+
+// listKeys returns the keys of an OpenConfig style list entry, i.e. the
+// scalar members of an entry also holding a config or state container.
+func listKeys(entry map[string]interface{}) (map[string]string, bool) {
+	container := false
+	keys := make(map[string]string)
+	for name, member := range entry {
+		switch tv := member.(type) {
+		case map[string]interface{}:
+			if n := localName(name); n == "config" || n == "state" {
+				container = true
+			}
+		case string:
+			keys[localName(name)] = tv
+		case json.Number:
+			keys[localName(name)] = tv.String()
+		case bool:
+			keys[localName(name)] = strconv.FormatBool(tv)
+		}
+	}
+	return keys, container && len(keys) > 0
+}
+
+// json2Updates walks decoded JSON data and appends an update for every leaf.
+// Leaf-lists are sent as a LeaflistVal. Lists whose keys can't be told
+// without the schema are sent as a single JSON_IETF value.
+func json2Updates(data interface{}, elems []*gnmipb.PathElem, updates *[]*gnmipb.Update) error {
+	switch v := data.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			member := v[name]
+			if list, ok := member.([]interface{}); ok && isObjectList(list) {
+				if err := list2Updates(name, list, elems, updates); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := json2Updates(member, appendElem(elems, &gnmipb.PathElem{Name: name}), updates); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		leaflist := &gnmipb.ScalarArray{}
+		for _, item := range v {
+			tv, ok := json2Scalar(item)
+			if !ok {
+				return fmt.Errorf("unsupported leaf-list item %v", item)
+			}
+			leaflist.Element = append(leaflist.Element, tv)
+		}
+		*updates = append(*updates, &gnmipb.Update{
+			Path: &gnmipb.Path{Elem: elems},
+			Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_LeaflistVal{LeaflistVal: leaflist}},
+		})
+		return nil
+	default:
+		tv, ok := json2Scalar(v)
+		if !ok {
+			return fmt.Errorf("unsupported json value %v", v)
+		}
+		*updates = append(*updates, &gnmipb.Update{Path: &gnmipb.Path{Elem: elems}, Val: tv})
+		return nil
+	}
+}
+
+// isObjectList tells whether a JSON array is a list of entries.
+func isObjectList(list []interface{}) bool {
+	for _, item := range list {
+		if _, ok := item.(map[string]interface{}); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// list2Updates appends the updates of the entries of a list.
+func list2Updates(name string, list []interface{}, elems []*gnmipb.PathElem, updates *[]*gnmipb.Update) error {
+	for _, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if ok {
+			_, ok = listKeys(entry)
+		}
+		if !ok {
+			// Keys unknown, send the whole list as it is.
+			jv, err := json.Marshal(list)
+			if err != nil {
+				return err
+			}
+			*updates = append(*updates, &gnmipb.Update{
+				Path: &gnmipb.Path{Elem: appendElem(elems, &gnmipb.PathElem{Name: name})},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: jv}},
+			})
+			return nil
+		}
+	}
+	for _, item := range list {
+		entry := item.(map[string]interface{})
+		keys, _ := listKeys(entry)
+		if err := json2Updates(entry, appendElem(elems, &gnmipb.PathElem{Name: name, Key: keys}), updates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
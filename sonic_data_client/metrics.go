@@ -0,0 +1,14 @@
+package client
+
+import (
+	"github.com/Azure/sonic-telemetry/metrics"
+)
+
+var (
+	redisCallLatency = metrics.NewHistogram("telemetry_redis_call_duration_seconds",
+		"Latency of the redis calls to the SONiC DBs.", metrics.DefBuckets, "db", "cmd")
+	dbClientMsgs = metrics.NewCounter("telemetry_db_client_messages_total",
+		"Messages enqueued by the DB data clients of subscriptions.", "target")
+	dbClientErrors = metrics.NewCounter("telemetry_db_client_errors_total",
+		"Fatal errors of the DB data clients of subscriptions.", "target")
+)
@@ -416,7 +416,8 @@ func (c *NonDbClient) String() string {
 		c.prefix.GetTarget(), c.sendMsg, c.recvMsg)
 }
 
-// StreamRun implements stream subscription for non-DB queries. It supports SAMPLE mode only.
+// StreamRun implements stream subscription for non-DB queries. It supports SAMPLE mode only,
+// TARGET_DEFINED subscriptions being sampled.
 func (c *NonDbClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *sync.WaitGroup, subscribe *gnmipb.SubscriptionList) {
 	c.w = w
 	defer c.w.Done()
@@ -426,6 +427,13 @@ func (c *NonDbClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *s
 
 	// Validate all subs
 	for _, sub := range subscribe.GetSubscription() {
+		if sub.GetMode() == gnmipb.SubscriptionMode_TARGET_DEFINED {
+			var table string
+			if elems := gnmiFullPath(c.prefix, sub.GetPath()).GetElem(); len(elems) > 0 {
+				table = elems[0].GetName()
+			}
+			sub = resolveTargetDefined(sub, "OTHERS", table)
+		}
 		subMode := sub.GetMode()
 		if subMode != gnmipb.SubscriptionMode_SAMPLE {
 			putFatalMsg(c.q, fmt.Sprintf("Unsupported subscription mode: %v.", subMode))
@@ -460,7 +468,7 @@ func (c *NonDbClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *s
 	}
 
 	c.q.Put(Value{
-		&spb.Value{
+		Value: &spb.Value{
 			Timestamp:    time.Now().UnixNano(),
 			SyncResponse: true,
 		},
@@ -512,7 +520,7 @@ func runGetterAndSend(c *NonDbClient, gnmiPath *gnmipb.Path, getter dataGetFunc)
 			}},
 	}
 
-	err = c.q.Put(Value{spbv})
+	err = c.q.Put(Value{Value: spbv})
 	if err != nil {
 		log.V(3).Infof("Failed to put for %v, %v", gnmiPath, err)
 	} else {
@@ -539,7 +547,7 @@ func (c *NonDbClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, w *syn
 		}
 
 		c.q.Put(Value{
-			&spb.Value{
+			Value: &spb.Value{
 				Timestamp:    time.Now().UnixNano(),
 				SyncResponse: true,
 			},
@@ -547,8 +555,33 @@ func (c *NonDbClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, w *syn
 		log.V(4).Infof("Sync done, poll time taken: %v ms", int64(time.Since(t1)/time.Millisecond))
 	}
 }
+// OnceRun implements Subscription "ONCE" mode for non-DB queries. It sends the
+// current value of every subscribed path followed by a sync_response.
 func (c *NonDbClient) OnceRun(q *queue.PriorityQueue, once chan struct{}, w *sync.WaitGroup, subscribe *gnmipb.SubscriptionList) {
-	return
+	c.w = w
+	defer c.w.Done()
+	c.q = q
+	c.channel = once
+
+	_, more := <-c.channel
+	if !more {
+		log.V(1).Infof("%v once channel closed, exiting onceRun routine", c)
+		return
+	}
+	t1 := time.Now()
+	if !subscribe.GetUpdatesOnly() {
+		for gnmiPath, getter := range c.path2Getter {
+			runGetterAndSend(c, gnmiPath, getter)
+		}
+	}
+
+	c.q.Put(Value{
+		Value: &spb.Value{
+			Timestamp:    time.Now().UnixNano(),
+			SyncResponse: true,
+		},
+	})
+	log.V(4).Infof("Sync done, once time taken: %v ms", int64(time.Since(t1)/time.Millisecond))
 }
 func (c *NonDbClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 	// wait sync for Get, not used for now
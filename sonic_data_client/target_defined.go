@@ -0,0 +1,127 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// TargetDefinedMode is the mode TARGET_DEFINED subscriptions of a target or
+// table are streamed with.
+type TargetDefinedMode struct {
+	// ON_CHANGE or SAMPLE
+	Mode gnmipb.SubscriptionMode
+	// Sample interval of SAMPLE, raised to MinSampleInterval
+	SampleInterval time.Duration
+}
+
+// DefaultTargetDefinedModes streams the tables of the config and state DBs
+// on change, and samples the counters and the OTHERS paths.
+const DefaultTargetDefinedModes = "CONFIG_DB=on_change,APPL_DB=on_change,STATE_DB=on_change,COUNTERS_DB=sample:10s,OTHERS=sample:10s"
+
+var (
+	targetDefinedMu    sync.RWMutex
+	targetDefinedModes map[string]TargetDefinedMode
+)
+
+func init() {
+	modes, err := ParseTargetDefinedModes(DefaultTargetDefinedModes)
+	if err != nil {
+		panic(err)
+	}
+	SetTargetDefinedModes(modes)
+}
+
+// ParseTargetDefinedModes parses comma separated modes of targets or
+// tables, like COUNTERS_DB=sample:10s or COUNTERS_DB/COUNTERS=sample:5s, the
+// mode being on_change or sample and its interval. The OTHERS target is
+// sampled only, its tables being the first element of the paths.
+func ParseTargetDefinedModes(s string) (map[string]TargetDefinedMode, error) {
+	modes := map[string]TargetDefinedMode{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid TARGET_DEFINED mode %q, expected target[/table]=mode", item)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		mv := strings.SplitN(value, ":", 2)
+		var mode TargetDefinedMode
+		switch strings.ToLower(mv[0]) {
+		case "on_change":
+			if len(mv) > 1 {
+				return nil, fmt.Errorf("invalid TARGET_DEFINED mode of %v: on_change has no interval", key)
+			}
+			if strings.SplitN(key, "/", 2)[0] == "OTHERS" {
+				return nil, fmt.Errorf("invalid TARGET_DEFINED mode of %v: OTHERS can only be sampled", key)
+			}
+			mode.Mode = gnmipb.SubscriptionMode_ON_CHANGE
+		case "sample":
+			mode.Mode = gnmipb.SubscriptionMode_SAMPLE
+			if len(mv) > 1 {
+				interval, err := time.ParseDuration(mv[1])
+				if err != nil || interval < 0 {
+					return nil, fmt.Errorf("invalid TARGET_DEFINED sample interval of %v: %q", key, mv[1])
+				}
+				mode.SampleInterval = interval
+			}
+		default:
+			return nil, fmt.Errorf("invalid TARGET_DEFINED mode of %v: %q, expected on_change or sample", key, mv[0])
+		}
+		modes[key] = mode
+	}
+	return modes, nil
+}
+
+// SetTargetDefinedModes changes the modes of new TARGET_DEFINED
+// subscriptions.
+func SetTargetDefinedModes(modes map[string]TargetDefinedMode) {
+	targetDefinedMu.Lock()
+	defer targetDefinedMu.Unlock()
+	targetDefinedModes = modes
+}
+
+// targetDefinedMode returns the mode of the TARGET_DEFINED subscriptions of
+// a table of a target: the one of the table, else the one of the target.
+// The unlisted DB targets are streamed on change, the unlisted OTHERS
+// tables sampled.
+func targetDefinedMode(target, table string) TargetDefinedMode {
+	targetDefinedMu.RLock()
+	defer targetDefinedMu.RUnlock()
+	if mode, ok := targetDefinedModes[target+"/"+table]; ok {
+		return mode
+	}
+	if mode, ok := targetDefinedModes[target]; ok {
+		return mode
+	}
+	if target == "OTHERS" {
+		return TargetDefinedMode{Mode: gnmipb.SubscriptionMode_SAMPLE}
+	}
+	return TargetDefinedMode{Mode: gnmipb.SubscriptionMode_ON_CHANGE}
+}
+
+// resolveTargetDefined returns a subscription with the mode of a
+// TARGET_DEFINED one, keeping its path.
+func resolveTargetDefined(sub *gnmipb.Subscription, target, table string) *gnmipb.Subscription {
+	mode := targetDefinedMode(target, table)
+	resolved := &gnmipb.Subscription{
+		Path:              sub.GetPath(),
+		Mode:              mode.Mode,
+		SuppressRedundant: sub.GetSuppressRedundant(),
+		HeartbeatInterval: sub.GetHeartbeatInterval(),
+	}
+	if mode.Mode == gnmipb.SubscriptionMode_SAMPLE {
+		interval := mode.SampleInterval
+		if min := minSampleInterval(); interval < min {
+			interval = min
+		}
+		resolved.SampleInterval = uint64(interval)
+	}
+	return resolved
+}
@@ -38,6 +38,7 @@ type TranslClient struct {
 	mu     sync.RWMutex    // Mutex for data protection among routines for transl_client
 	ctx context.Context //Contains Auth info and request info
 	extensions []*gnmi_extpb.Extension
+	dataType gnmipb.GetRequest_DataType //Data type requested by GetRequest
 }
 
 func NewTranslClient(prefix *gnmipb.Path, getpaths []*gnmipb.Path, ctx context.Context, extensions []*gnmi_extpb.Extension) (Client, error) {
@@ -59,6 +60,16 @@ func NewTranslClient(prefix *gnmipb.Path, getpaths []*gnmipb.Path, ctx context.C
 	}
 }
 
+/* Create Transl client for a GetRequest of the given data type. */
+func NewTranslGetClient(prefix *gnmipb.Path, getpaths []*gnmipb.Path, ctx context.Context, extensions []*gnmi_extpb.Extension, dataType gnmipb.GetRequest_DataType) (Client, error) {
+	dc, err := NewTranslClient(prefix, getpaths, ctx, extensions)
+	if err != nil {
+		return nil, err
+	}
+	dc.(*TranslClient).dataType = dataType
+	return dc, nil
+}
+
 func (c *TranslClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 	rc, ctx := common_utils.GetContext(c.ctx)
 	c.ctx = ctx
@@ -72,7 +83,7 @@ func (c *TranslClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 	/* Iterate through all GNMI paths. */
 	for gnmiPath, URIPath := range c.path2URI {
 		/* Fill values for each GNMI path. */
-		val, err := transutil.TranslProcessGet(URIPath, nil, c.ctx)
+		val, err := transutil.TranslProcessGet(URIPath, nil, c.ctx, c.dataType)
 
 		if err != nil {
 			return nil, err
@@ -128,7 +139,7 @@ func (c *TranslClient) Set(delete []*gnmipb.Path, replace []*gnmipb.Update, upda
 }
 func enqueFatalMsgTranslib(c *TranslClient, msg string) {
 	c.q.Put(Value{
-		&spb.Value{
+		Value: &spb.Value{
 			Timestamp: time.Now().UnixNano(),
 			Fatal:     msg,
 		},
@@ -221,7 +232,7 @@ func (c *TranslClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *
 			}
 			if !subscribe.UpdatesOnly {
 				//Send initial data now so we can send sync response.
-				val, err := transutil.TranslProcessGet(c.path2URI[sub.Path], nil, c.ctx)
+				val, err := transutil.TranslProcessGet(c.path2URI[sub.Path], nil, c.ctx, gnmipb.GetRequest_ALL)
 				if err != nil {
 					return
 				}
@@ -232,7 +243,7 @@ func (c *TranslClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *
 					SyncResponse: false,
 					Val:          val,
 				}
-				c.q.Put(Value{spbv})
+				c.q.Put(Value{Value: spbv})
 				valueCache[c.path2URI[sub.Path]] = string(val.GetJsonIetfVal())
 			}
 
@@ -273,7 +284,7 @@ func (c *TranslClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *
 		Timestamp:    time.Now().UnixNano(),
 		SyncResponse: true,
 	}
-	c.q.Put(Value{spbs})
+	c.q.Put(Value{Value: spbs})
 	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c.channel)})
 
 	for {
@@ -286,7 +297,7 @@ func (c *TranslClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *
 
 		for _,tick := range ticker_map[cases_map[chosen]] {
 			fmt.Printf("tick, heartbeat: %t, path: %s", tick.heartbeat, c.path2URI[tick.sub.Path])
-			val, err := transutil.TranslProcessGet(c.path2URI[tick.sub.Path], nil, c.ctx)
+			val, err := transutil.TranslProcessGet(c.path2URI[tick.sub.Path], nil, c.ctx, gnmipb.GetRequest_ALL)
 			if err != nil {
 				return
 			}
@@ -302,7 +313,7 @@ func (c *TranslClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *
 			if (tick.sub.SuppressRedundant) && (!tick.heartbeat) && (string(val.GetJsonIetfVal()) == valueCache[c.path2URI[tick.sub.Path]]) {
 				log.V(6).Infof("Redundant Message Suppressed #%v", string(val.GetJsonIetfVal()))
 			} else {
-				c.q.Put(Value{spbv})
+				c.q.Put(Value{Value: spbv})
 				valueCache[c.path2URI[tick.sub.Path]] = string(val.GetJsonIetfVal())
 				log.V(6).Infof("Added spbv #%v", spbv)
 			}
@@ -390,7 +401,7 @@ func TranslSubscribe(gnmiPaths []*gnmipb.Path, stringPaths []string, pathMap map
 			if updates_only && !sync_done {
 				log.V(1).Infof("Msg suppressed due to updates_only")
 			} else {
-				c.q.Put(Value{spbv})
+				c.q.Put(Value{Value: spbv})
 			}
 
 			log.V(6).Infof("Added spbv #%v", spbv)
@@ -429,7 +440,7 @@ func (c *TranslClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, w *sy
 		t1 := time.Now()
 		for gnmiPath, URIPath := range c.path2URI {
 			if synced || !subscribe.UpdatesOnly {
-				val, err := transutil.TranslProcessGet(URIPath, nil, c.ctx)
+				val, err := transutil.TranslProcessGet(URIPath, nil, c.ctx, gnmipb.GetRequest_ALL)
 				if err != nil {
 					return
 				}
@@ -442,13 +453,13 @@ func (c *TranslClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, w *sy
 					Val:          val,
 				}
 
-				c.q.Put(Value{spbv})
+				c.q.Put(Value{Value: spbv})
 				log.V(6).Infof("Added spbv #%v", spbv)
 			}
 		}
 
 		c.q.Put(Value{
-			&spb.Value{
+			Value: &spb.Value{
 				Timestamp:    time.Now().UnixNano(),
 				SyncResponse: true,
 			},
@@ -476,7 +487,7 @@ func (c *TranslClient) OnceRun(q *queue.PriorityQueue, once chan struct{}, w *sy
 	}
 	t1 := time.Now()
 	for gnmiPath, URIPath := range c.path2URI {
-		val, err := transutil.TranslProcessGet(URIPath, nil, c.ctx)
+		val, err := transutil.TranslProcessGet(URIPath, nil, c.ctx, gnmipb.GetRequest_ALL)
 		if err != nil {
 			return
 		}
@@ -490,13 +501,13 @@ func (c *TranslClient) OnceRun(q *queue.PriorityQueue, once chan struct{}, w *sy
 				Val:          val,
 			}
 
-			c.q.Put(Value{spbv})
+			c.q.Put(Value{Value: spbv})
 			log.V(6).Infof("Added spbv #%v", spbv)
 		}
 	}
 
 	c.q.Put(Value{
-		&spb.Value{
+		Value: &spb.Value{
 			Timestamp:    time.Now().UnixNano(),
 			SyncResponse: true,
 		},
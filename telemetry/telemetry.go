@@ -2,22 +2,30 @@ package main
 
 import (
 	"crypto/tls"
-	"crypto/x509"
 	"flag"
-	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	log "github.com/golang/glog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
+	"github.com/Azure/sonic-telemetry/audit"
 	gnmi "github.com/Azure/sonic-telemetry/gnmi_server"
+	"github.com/Azure/sonic-telemetry/metrics"
+	sdc "github.com/Azure/sonic-telemetry/sonic_data_client"
 	testcert "github.com/Azure/sonic-telemetry/testdata/tls"
 )
 
 var (
         userAuth = gnmi.AuthTypes{"password": false, "cert": false, "jwt": false}
+	queuePolicy = gnmi.QueueConflate
 	port = flag.Int("port", -1, "port to listen on")
+	listenAddrs = flag.String("listen_addr", "", "Comma separated addresses to listen on instead of port on all interfaces, like 10.1.0.32:8080,[fc00::1]:8080. Optional.")
+	unixSocket  = flag.String("unix_socket", "", "Path of an additional unix socket listener, without TLS, whose clients are identified by their peer credentials. Optional.")
 	// Certificate files.
 	caCert            = flag.String("ca_crt", "", "CA certificate for client certificate validation. Optional.")
 	serverCert        = flag.String("server_crt", "", "TLS server certificate")
@@ -25,49 +33,167 @@ var (
 	insecure          = flag.Bool("insecure", false, "Skip providing TLS cert and key, for testing only!")
 	noTLS             = flag.Bool("noTLS", false, "disable TLS, for testing only!")
 	allowNoClientCert = flag.Bool("allow_no_client_auth", false, "When set, telemetry server will request but not require a client certificate.")
+	clientCertId      = flag.String("client_cert_identity", "cn", "Comma separated fields of client certificates their identity is taken from, in order of preference - cn,san_dns,san_uri,san_email,ou")
+	clientCertMap     = flag.String("client_cert_map", "", "JSON file mapping client certificate identities to local users and roles. Optional.")
+	clientCrlDir      = flag.String("client_crl_dir", "", "Directory of the CRL files client certificates are checked against, reloaded every tls_reload_interval. Optional.")
+	passwordBackend   = flag.String("password_backend", "ssh", "Backend checking the passwords of client_auth mode password - ssh,pam,htpasswd")
+	passwordFile      = flag.String("password_file", "", "htpasswd file of the bcrypt hashes of the passwords, for password_backend htpasswd.")
+	passwordCacheTTL  = flag.Duration("password_cache_ttl", 10*time.Second, "Time successful password checks are remembered for, 0 to disable. Passwords changed or removed in the ssh and pam backends keep working for that long.")
+	authMaxFailures   = flag.Int("auth_max_failures", 0, "Failed password attempts of a user or a source address before they are locked out, 0 to disable lockouts.")
+	authLockout       = flag.Duration("auth_lockout", 30*time.Second, "Duration of the first lockout, doubled by each further failure.")
+	authLockoutMax    = flag.Duration("auth_lockout_max", 15*time.Minute, "Longest lockout. Failures are forgotten after that long without failure.")
 	jwtRefInt         = flag.Uint64("jwt_refresh_int", 900, "Seconds before JWT expiry the token can be refreshed.")
 	jwtValInt         = flag.Uint64("jwt_valid_int", 3600, "Seconds that JWT token is valid for.")
+	jwtKeyFile        = flag.String("jwt_key_file", "", "JSON file of the keys signing JWT tokens, only readable by its owner. A random key valid until restart is used if not set.")
+	jwtKeyReload      = flag.Duration("jwt_key_reload_interval", 30*time.Second, "Interval at which the jwt_key_file and jwt_oidc_config files are checked for changes and reloaded, 0 to disable.")
+	jwtAlg            = flag.String("jwt_alg", "HS256", "Algorithm of the random key signing JWT tokens when jwt_key_file is not set - HS256,RS256,ES256")
+	jwtOidcConfig     = flag.String("jwt_oidc_config", "", "JSON file of the trusted OIDC issuer whose JWT tokens are accepted besides the tokens of the server. Optional.")
+	jwtDenylistDb     = flag.Bool("jwt_denylist_db", false, "When set, revoked JWT tokens are persisted in STATE_DB and stay revoked after restarts.")
+	jwtJwksFile       = flag.String("jwt_jwks_file", "", "File the public keys of the RS256 and ES256 JWT signing keys are written to as a JWK set. Optional.")
+	authPolicy        = flag.String("auth_policy", "", "JSON file of the client auth mechanisms required by RPC, like cert+password for Set. Optional.")
+	authzPolicy       = flag.String("authz_policy", "", "JSON file of the role based path authorization policy. Optional.")
+	authzConfigDb     = flag.Bool("authz_config_db", false, "When set, the role based path authorization policy is read from the GNMI_AUTHZ table of CONFIG_DB, and reloaded when it changes.")
+	auditSyslog       = flag.Bool("audit_syslog", false, "When set, audit records of all operations are sent to syslog.")
+	auditFile         = flag.String("audit_file", "", "File audit records of all operations are written to as JSON lines. Optional.")
+	auditFileMaxSize  = flag.Int64("audit_file_max_size", 10, "Size in MB the audit file is rotated at.")
+	auditFileBackups  = flag.Int("audit_file_backups", 5, "Number of rotated audit files kept.")
+	queueLimit        = flag.Int("client_queue_limit", 0, "Maximum number of updates waiting to be sent to a Subscribe client, 0 for no limit.")
+	tlsReloadInterval = flag.Duration("tls_reload_interval", 30*time.Second, "Interval at which the server_crt, server_key and ca_crt files are checked for changes and reloaded, 0 to disable.")
+	minSampleInterval = flag.Duration("min_sample_interval", time.Second, "Lowest sample interval of SAMPLE subscriptions.")
+	targetDefined     = flag.String("target_defined", sdc.DefaultTargetDefinedModes, "Comma separated modes of the TARGET_DEFINED subscriptions of DB targets, tables and OTHERS, like COUNTERS_DB/COUNTERS=sample:5s - on_change,sample[:interval]")
+	configFile        = flag.String("config_file", "", "YAML or JSON file of settings named after the flags, for the flags not set on the command line. Optional.")
+	configDb          = flag.Bool("config_db", false, "When set, settings are read from the TELEMETRY|gnmi_settings entry of CONFIG_DB, taking precedence over config_file, and their changes applied at runtime when possible.")
+	shutdownTimeout   = flag.Duration("shutdown_timeout", 10*time.Second, "Time given to the RPCs in flight to complete on SIGTERM or SIGINT before the server is stopped.")
+	metricsAddr       = flag.String("metrics_addr", "", "Address of the HTTP metrics endpoint, like :8081 or unix:/var/run/telemetry_metrics.sock. Optional.")
 )
 
 func main() {
 	flag.Var(userAuth, "client_auth", "Client auth mode(s) - none,cert,password")
+	flag.Var(&queuePolicy, "client_queue_policy", "Policy applied when the queue of a Subscribe client is full - conflate,drop-oldest,disconnect")
 	flag.Parse()
 
-	var defUserAuth gnmi.AuthTypes
-	if gnmi.READ_WRITE_MODE {
-		//In read/write mode we want to enable auth by default.
-		defUserAuth = gnmi.AuthTypes{"password": true, "cert": false, "jwt": true}
-	}else {
-		defUserAuth = gnmi.AuthTypes{"jwt": false, "password": false, "cert": false}
+	// Settings apply to the flags not set on the command line
+	cmdline := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		cmdline[f.Name] = true
+	})
+	if *configFile != "" {
+		settings, err := gnmi.LoadSettingsFile(*configFile)
+		if err != nil {
+			log.Exitf("could not load settings: %v", err)
+		}
+		if err := settings.SetFlags(flag.CommandLine, cmdline); err != nil {
+			log.Exitf("could not apply settings of %v: %v", *configFile, err)
+		}
+	}
+	if *configDb {
+		settings, err := gnmi.LoadSettingsDb()
+		if err != nil {
+			log.Exitf("could not load settings: %v", err)
+		}
+		if err := settings.SetFlags(flag.CommandLine, cmdline); err != nil {
+			log.Exitf("could not apply settings of CONFIG_DB: %v", err)
+		}
 	}
 
-        if isFlagPassed("client_auth") {
-                log.V(1).Infof("client_auth provided")
-        }else {
-                log.V(1).Infof("client_auth not provided, using defaults.")
-                userAuth = defUserAuth
-        }
+	// Without client_auth, the RPCs are not authenticated, whatever the
+	// build mode, as the server always did.
+	if isFlagPassed("client_auth") {
+		log.V(1).Infof("client_auth provided")
+	} else {
+		log.V(1).Infof("client_auth not provided, client authentication disabled")
+		userAuth = gnmi.AuthTypes{"jwt": false, "password": false, "cert": false}
+	}
 
 	switch {
-	case *port <= 0:
+	case *port <= 0 && *listenAddrs == "":
 		log.Errorf("port must be > 0.")
 		return
 	}
+	sdc.SetMinSampleInterval(*minSampleInterval)
+	modes, err := sdc.ParseTargetDefinedModes(*targetDefined)
+	if err != nil {
+		log.Exitf("invalid target_defined: %v", err)
+	}
+	sdc.SetTargetDefinedModes(modes)
 	gnmi.JwtRefreshInt = time.Duration(*jwtRefInt*uint64(time.Second))
 	gnmi.JwtValidInt = time.Duration(*jwtValInt*uint64(time.Second))
+	backend, err := gnmi.NewPasswordBackend(*passwordBackend, *passwordFile)
+	if err != nil {
+		log.Exitf("could not create password backend: %v", err)
+	}
+	gnmi.SetPasswordAuth(backend, *passwordCacheTTL)
 
 	cfg := &gnmi.Config{}
 	cfg.Port = int64(*port)
+	if *listenAddrs != "" {
+		cfg.Addrs = strings.Split(*listenAddrs, ",")
+	}
+	cfg.UnixSocket = *unixSocket
+	cfg.QueueLimit = *queueLimit
+	cfg.QueuePolicy = queuePolicy
 	var opts []grpc.ServerOption
 
+	var auditSinks []audit.Sink
+	if *auditSyslog {
+		sink, err := audit.NewSyslogSink("telemetry")
+		if err != nil {
+			log.Exitf("could not create audit syslog sink: %v", err)
+		}
+		auditSinks = append(auditSinks, sink)
+	}
+	if *auditFile != "" {
+		sink, err := audit.NewFileSink(*auditFile, *auditFileMaxSize*1024*1024, *auditFileBackups)
+		if err != nil {
+			log.Exitf("could not create audit file sink: %v", err)
+		}
+		auditSinks = append(auditSinks, sink)
+	}
+	if len(auditSinks) > 0 {
+		cfg.Audit = audit.NewLogger(auditSinks...)
+		defer cfg.Audit.Close()
+	}
+	gnmi.SetAuthLockout(gnmi.AuthLockoutPolicy{
+		MaxFailures: *authMaxFailures,
+		Duration:    *authLockout,
+		MaxDuration: *authLockoutMax,
+	}, cfg.Audit)
+
+	if *authPolicy != "" {
+		policy, err := gnmi.LoadAuthPolicyFile(*authPolicy)
+		if err != nil {
+			log.Exitf("could not load auth policy: %v", err)
+		}
+		gnmi.SetAuthPolicy(policy)
+	}
+
+	switch {
+	case *authzPolicy != "" && *authzConfigDb:
+		log.Errorf("authz_policy and authz_config_db are mutually exclusive.")
+		return
+	case *authzPolicy != "":
+		policy, err := gnmi.LoadAuthzPolicyFile(*authzPolicy)
+		if err != nil {
+			log.Exitf("could not load authorization policy: %v", err)
+		}
+		cfg.AuthzPolicy = policy
+	case *authzConfigDb:
+		policy, err := gnmi.LoadAuthzPolicyDb()
+		if err != nil {
+			log.Exitf("could not load authorization policy: %v", err)
+		}
+		cfg.AuthzPolicy = policy
+	}
+
 	if !*noTLS {
-		var certificate tls.Certificate
-		var err error
+		var certificates []tls.Certificate
+		var certFile, keyFile string
 		if *insecure {
-			certificate, err = testcert.NewCert()
+			certificate, err := testcert.NewCert()
 			if err != nil {
 				log.Exitf("could not load server key pair: %s", err)
 			}
+			certificates = []tls.Certificate{certificate}
 		} else {
 			 switch {
 			   case *serverCert == "":
@@ -77,15 +203,12 @@ func main() {
 				  log.Errorf("serverKey must be set.")
 				  return
 			}
-			certificate, err = tls.LoadX509KeyPair(*serverCert, *serverKey)
-			if err != nil {
-				log.Exitf("could not load server key pair: %s", err)
-			}
+			certFile, keyFile = *serverCert, *serverKey
 		}
 
 		tlsCfg := &tls.Config{
 		ClientAuth:   tls.RequireAndVerifyClientCert,
-		Certificates: []tls.Certificate{certificate},
+		Certificates: certificates,
 		MinVersion:               tls.VersionTLS12,
 		CurvePreferences:         []tls.CurveID{tls.CurveP521, tls.CurveP384, tls.CurveP256},
 		PreferServerCipherSuites: true,
@@ -106,30 +229,75 @@ func main() {
 		tlsCfg.ClientAuth = tls.RequestClientCert
 	}
 
-	if *caCert != "" {
-		ca, err := ioutil.ReadFile(*caCert)
-		if err != nil {
-			log.Exitf("could not read CA certificate: %s", err)
-		}
-		certPool := x509.NewCertPool()
-		if ok := certPool.AppendCertsFromPEM(ca); !ok {
-			log.Exit("failed to append CA certificate")
-		}
-		tlsCfg.ClientCAs = certPool
-	} else {
+	if *caCert == "" {
 		if userAuth.Enabled("cert") {
 			userAuth.Unset("cert")
 			log.Warning("client_auth mode cert requires ca_crt option. Disabling cert mode authentication.")
 		}
 	}
+	// The certificate and CA files are reloaded when they change, for
+	// new connections.
+	reloader, err := gnmi.NewTLSReloader(tlsCfg, certFile, keyFile, *caCert)
+	if err != nil {
+		log.Exitf("could not load TLS certificates: %s", err)
+	}
+	if *tlsReloadInterval > 0 {
+		go reloader.Watch(*tlsReloadInterval, nil)
+	}
 
-	opts = []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))}
-	cfg := &gnmi.Config{}
-	cfg.Port = int64(*port)
-	cfg.UserAuth = userAuth
+	opts = []grpc.ServerOption{grpc.Creds(credentials.NewTLS(reloader.Config()))}
 
-	gnmi.GenerateJwtSecretKey()
+	if err := gnmi.SetClientCertIdentity(strings.Split(*clientCertId, ",")); err != nil {
+		log.Exitf("invalid client_cert_identity: %v", err)
+	}
+	if *clientCertMap != "" {
+		if err := gnmi.LoadClientCertMap(*clientCertMap); err != nil {
+			log.Exitf("could not load client certificate map: %v", err)
+		}
+		if *tlsReloadInterval > 0 {
+			go gnmi.WatchClientCertMap(*clientCertMap, *tlsReloadInterval, nil)
+		}
+	}
+	if *clientCrlDir != "" {
+		if err := gnmi.LoadClientCrlDir(*clientCrlDir); err != nil {
+			log.Exitf("could not load client CRLs: %v", err)
+		}
+		if *tlsReloadInterval > 0 {
+			go gnmi.WatchClientCrlDir(*clientCrlDir, *tlsReloadInterval, nil)
+		}
+	}
+	gnmi.JwtJwksFile = *jwtJwksFile
+	if *jwtKeyFile != "" {
+		if err := gnmi.LoadJwtKeyFile(*jwtKeyFile); err != nil {
+			log.Exitf("could not load JWT keys: %v", err)
+		}
+		if *jwtKeyReload > 0 {
+			go gnmi.WatchJwtKeyFile(*jwtKeyFile, *jwtKeyReload, nil)
+		}
+	} else if err := gnmi.GenerateJwtKey(*jwtAlg); err != nil {
+		log.Exitf("could not generate JWT key: %v", err)
+	}
+	if *jwtOidcConfig != "" {
+		if err := gnmi.LoadJwtOidcConfig(*jwtOidcConfig); err != nil {
+			log.Exitf("could not load OIDC config: %v", err)
+		}
+		if *jwtKeyReload > 0 {
+			go gnmi.WatchJwtOidcConfig(*jwtOidcConfig, *jwtKeyReload, nil)
+		}
+	}
+	if *jwtDenylistDb {
+		gnmi.JwtDenylistDb = true
+		if err := gnmi.LoadJwtDenylistDb(); err != nil {
+			log.Exitf("could not load revoked JWT tokens: %v", err)
+		}
+	}
 }
+	// The auth modes apply with and without TLS. Rules of the auth policy
+	// requiring disabled modes would fail all their RPCs.
+	if err := gnmi.CheckAuthPolicy(userAuth); err != nil {
+		log.Exitf("auth_policy does not match client_auth: %v", err)
+	}
+	cfg.UserAuth = userAuth
 
 	s, err := gnmi.NewServer(cfg, opts)
 	if err != nil {
@@ -137,9 +305,50 @@ func main() {
 		return
 	}
 
-	log.V(1).Infof("Auth Modes: ", userAuth)
+	if *metricsAddr != "" {
+		lis, err := metrics.Listen(*metricsAddr)
+		if err != nil {
+			log.Exitf("could not open metrics listener: %v", err)
+		}
+		metrics.Register(s)
+		go func() {
+			log.Errorf("Metrics endpoint stopped: %v", metrics.Serve(lis))
+		}()
+	}
+
+	if *configDb {
+		go func() {
+			if err := s.WatchSettingsDb(nil); err != nil {
+				log.Errorf("Failed to watch settings: %v", err)
+			}
+		}()
+	}
+	if *authzConfigDb {
+		go func() {
+			if err := s.WatchAuthzPolicyDb(nil); err != nil {
+				log.Errorf("Failed to watch authorization policy: %v", err)
+			}
+		}()
+	}
+
+	log.V(1).Infof("Auth Modes: %v", userAuth)
 	log.V(1).Infof("Starting RPC server on address: %s", s.Address())
-	s.Serve() // blocks until close
+	stopped := make(chan struct{})
+	go func() {
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+		sig := <-sigs
+		log.Infof("Received %v, shutting down", sig)
+		if err := s.Shutdown(*shutdownTimeout); err != nil {
+			log.Errorf("Shutdown: %v", err)
+		}
+		close(stopped)
+	}()
+	if err := s.Serve(); err != nil { // blocks until close
+		log.Errorf("Serve failed: %v", err)
+	} else {
+		<-stopped
+	}
 	log.Flush()
 }
 
@@ -12,11 +12,16 @@ import (
 	"context"
 	"log/syslog"
 	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
-
+	"github.com/Azure/sonic-telemetry/metrics"
+	"time"
 )
 
 var (
     Writer *syslog.Writer
+    translibCallLatency = metrics.NewHistogram("telemetry_translib_call_duration_seconds",
+        "Latency of the translib calls.", metrics.DefBuckets, "op")
+    // ImplTranslibGet points to the implementation of translib.Get. Should be overridden by UTs only.
+    ImplTranslibGet func(translib.GetRequest) (translib.GetResponse, error) = translib.Get
 )
 
 func __log_audit_msg(ctx context.Context, reqType string, uriPath string, err error) {
@@ -47,7 +52,7 @@ func __log_audit_msg(ctx context.Context, reqType string, uriPath string, err er
 
 func GnmiTranslFullPath(prefix, path *gnmipb.Path) *gnmipb.Path {
 
-	fullPath := &gnmipb.Path{Origin: path.Origin}
+	fullPath := &gnmipb.Path{Origin: path.GetOrigin()}
 	if path.GetElement() != nil {
 		fullPath.Element = append(prefix.GetElement(), path.GetElement()...)
 	}
@@ -109,13 +114,25 @@ func ConvertToURI(prefix *gnmipb.Path, path *gnmipb.Path, req *string) error {
 	return nil
 }
 
+/* Map gNMI GetRequest data type to translib content query parameter.
+   translib takes the RESTCONF content values, "config" and "nonconfig",
+   and "operational" for the OPERATIONAL data of gNMI. */
+var getContent = map[gnmipb.GetRequest_DataType]string{
+	gnmipb.GetRequest_CONFIG:      "config",
+	gnmipb.GetRequest_STATE:       "nonconfig",
+	gnmipb.GetRequest_OPERATIONAL: "operational",
+}
+
 /* Fill the values from TransLib. */
-func TranslProcessGet(uriPath string, op *string, ctx context.Context) (*gnmipb.TypedValue, error) {
+func TranslProcessGet(uriPath string, op *string, ctx context.Context, dataType gnmipb.GetRequest_DataType) (*gnmipb.TypedValue, error) {
+	defer translibCallLatency.ObserveSince(time.Now(), "get")
 	var jv []byte
 	var data []byte
 	rc, _ := common_utils.GetContext(ctx)
 
 	req := translib.GetRequest{Path:uriPath, User: translib.UserRoles{Name: rc.Auth.User, Roles: rc.Auth.Roles}}
+	/* Data type ALL needs no content filtering. */
+	req.QueryParams.Content = getContent[dataType]
 	if rc.BundleVersion != nil {
 		nver, err := translib.NewVersion(*rc.BundleVersion)
 		if err != nil {
@@ -127,7 +144,7 @@ func TranslProcessGet(uriPath string, op *string, ctx context.Context) (*gnmipb.
 	if rc.Auth.AuthEnabled {
 		req.AuthEnabled = true
 	}
-	resp, err1 := translib.Get(req)
+	resp, err1 := ImplTranslibGet(req)
 
 	if isTranslibSuccess(err1) {
 		data = resp.Payload
@@ -151,6 +168,7 @@ func TranslProcessGet(uriPath string, op *string, ctx context.Context) (*gnmipb.
 
 /* Delete request handling. */
 func TranslProcessDelete(uri string, ctx context.Context) error {
+	defer translibCallLatency.ObserveSince(time.Now(), "delete")
 	var str3 string
 	payload := []byte(str3)
 	rc, _ := common_utils.GetContext(ctx)
@@ -177,6 +195,7 @@ func TranslProcessDelete(uri string, ctx context.Context) error {
 
 /* Replace request handling. */
 func TranslProcessReplace(uri string, t *gnmipb.TypedValue, ctx context.Context) error {
+	defer translibCallLatency.ObserveSince(time.Now(), "replace")
 	/* Form the CURL request and send to client . */
 	str := string(t.GetJsonIetfVal())
 	str3 := strings.Replace(str, "\n", "", -1)
@@ -209,6 +228,7 @@ func TranslProcessReplace(uri string, t *gnmipb.TypedValue, ctx context.Context)
 
 /* Update request handling. */
 func TranslProcessUpdate(uri string, t *gnmipb.TypedValue, ctx context.Context) error {
+	defer translibCallLatency.ObserveSince(time.Now(), "update")
 	/* Form the CURL request and send to client . */
 	str := string(t.GetJsonIetfVal())
 	str3 := strings.Replace(str, "\n", "", -1)
@@ -247,6 +267,7 @@ func TranslProcessUpdate(uri string, t *gnmipb.TypedValue, ctx context.Context)
 }
 
 func TranslProcessBulk(delete []*gnmipb.Path, replace []*gnmipb.Update, update []*gnmipb.Update, prefix *gnmipb.Path, ctx context.Context) error {
+	defer translibCallLatency.ObserveSince(time.Now(), "bulk")
 	var br translib.BulkRequest
 	var uri string
 
@@ -342,28 +363,40 @@ func TranslProcessBulk(delete []*gnmipb.Path, replace []*gnmipb.Update, update [
             i++
         }
 
-	var errors []string
 	if err != nil{
 		log.V(2).Info("BULK SET operation failed with error(s):")
-		for _,d := range resp.DeleteResponse {
-			if d.Err != nil {
+		/* Report the error of each operation, in request order. */
+		setErr := common_utils.SetError{
+			Delete:  make([]error, len(delete)),
+			Replace: make([]error, len(replace)),
+			Update:  make([]error, len(update)),
+		}
+		failed := false
+		for i,d := range resp.DeleteResponse {
+			if d.Err != nil && i < len(delete) {
 				log.V(2).Infof("%s=%v", d.Err.Error(), d.ErrSrc)
-				errors = append(errors, d.Err.Error())
+				setErr.Delete[i] = d.Err
+				failed = true
 			}
 		}
-		for _,r := range resp.ReplaceResponse {
-			if r.Err != nil {
+		for i,r := range resp.ReplaceResponse {
+			if r.Err != nil && i < len(replace) {
 				log.V(2).Infof("%s=%v", r.Err.Error(), r.ErrSrc)
-				errors = append(errors, r.Err.Error())
+				setErr.Replace[i] = r.Err
+				failed = true
 			}
 		}
-		for _,u := range resp.UpdateResponse {
-			if u.Err != nil {
+		for i,u := range resp.UpdateResponse {
+			if u.Err != nil && i < len(update) {
 				log.V(2).Infof("%s=%v", u.Err.Error(), u.ErrSrc)
-				errors = append(errors, u.Err.Error())
+				setErr.Update[i] = u.Err
+				failed = true
 			}
 		}
-		return fmt.Errorf("SET failed: %s", strings.Join(errors, "; "))
+		if !failed {
+			return err
+		}
+		return &setErr
 	}
 
 	return nil
@@ -371,6 +404,7 @@ func TranslProcessBulk(delete []*gnmipb.Path, replace []*gnmipb.Update, update [
 
 /* Action/rpc request handling. */
 func TranslProcessAction(uri string, payload []byte, ctx context.Context) ([]byte, error) {
+	defer translibCallLatency.ObserveSince(time.Now(), "action")
 	rc, ctx := common_utils.GetContext(ctx)
 	req := translib.ActionRequest{User: translib.UserRoles{Name: rc.Auth.User, Roles: rc.Auth.Roles}}
 	if rc.BundleVersion != nil {
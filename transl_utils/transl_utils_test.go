@@ -0,0 +1,41 @@
+package transl_utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/sonic-mgmt-common/translib"
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestTranslProcessGetContent(t *testing.T) {
+	defer func() { ImplTranslibGet = translib.Get }()
+
+	tds := []struct {
+		dataType    gnmipb.GetRequest_DataType
+		wantContent string
+	}{
+		{gnmipb.GetRequest_ALL, ""},
+		{gnmipb.GetRequest_CONFIG, "config"},
+		{gnmipb.GetRequest_STATE, "nonconfig"},
+		{gnmipb.GetRequest_OPERATIONAL, "operational"},
+	}
+	for _, td := range tds {
+		t.Run(td.dataType.String(), func(t *testing.T) {
+			var got *translib.GetRequest
+			ImplTranslibGet = func(req translib.GetRequest) (translib.GetResponse, error) {
+				got = &req
+				return translib.GetResponse{Payload: []byte("{}")}, nil
+			}
+			if _, err := TranslProcessGet("/openconfig-interfaces:interfaces", nil, context.Background(), td.dataType); err != nil {
+				t.Fatalf("TranslProcessGet failed: %v", err)
+			}
+			if got == nil {
+				t.Fatalf("translib Get not called")
+			}
+			if got.QueryParams.Content != td.wantContent {
+				t.Errorf("got content %q, want %q", got.QueryParams.Content, td.wantContent)
+			}
+		})
+	}
+}